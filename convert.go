@@ -0,0 +1,292 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AsWebService converts a generic web service back into a WebService builder
+func (s *Service) AsWebService() (*WebService, error) {
+	if s.Type != ServiceTypeWeb || isStaticSite(*s) {
+		return nil, fmt.Errorf("service %s is not a web service", s.Name)
+	}
+	if s.Runtime == nil {
+		return nil, fmt.Errorf("service %s missing runtime", s.Name)
+	}
+
+	service := cloneOf(*s)
+	ws := &WebService{
+		Name:                    service.Name,
+		Runtime:                 *service.Runtime,
+		Domains:                 service.Domains,
+		HealthCheckPath:         service.HealthCheckPath,
+		StartCommand:            service.StartCommand,
+		Plan:                    service.Plan,
+		Region:                  service.Region,
+		Git:                     gitConfigOf(service),
+		Build:                   buildConfigOf(service),
+		Docker:                  dockerConfigOf(service),
+		Scaling:                 scalingConfigOf(service),
+		Preview:                 previewConfigOf(service),
+		EnvVars:                 service.EnvVars,
+		MaxShutdownDelaySeconds: service.MaxShutdownDelaySeconds,
+		Disk:                    service.Disk,
+		Tags:                    service.Tags,
+	}
+	return ws, checkConversion(s, ws)
+}
+
+// AsBackgroundWorker converts a generic worker service back into a BackgroundWorker builder
+func (s *Service) AsBackgroundWorker() (*BackgroundWorker, error) {
+	if s.Type != ServiceTypeWorker {
+		return nil, fmt.Errorf("service %s is not a background worker", s.Name)
+	}
+	if s.Runtime == nil {
+		return nil, fmt.Errorf("service %s missing runtime", s.Name)
+	}
+
+	service := cloneOf(*s)
+	bw := &BackgroundWorker{
+		Name:                    service.Name,
+		Runtime:                 *service.Runtime,
+		StartCommand:            service.StartCommand,
+		Plan:                    service.Plan,
+		Region:                  service.Region,
+		Git:                     gitConfigOf(service),
+		Build:                   buildConfigOf(service),
+		Docker:                  dockerConfigOf(service),
+		Preview:                 previewConfigOf(service),
+		EnvVars:                 service.EnvVars,
+		MaxShutdownDelaySeconds: service.MaxShutdownDelaySeconds,
+		Disk:                    service.Disk,
+		Tags:                    service.Tags,
+	}
+	return bw, checkConversion(s, bw)
+}
+
+// AsPrivateService converts a generic private service back into a PrivateService builder
+func (s *Service) AsPrivateService() (*PrivateService, error) {
+	if s.Type != ServiceTypePServ {
+		return nil, fmt.Errorf("service %s is not a private service", s.Name)
+	}
+	if s.Runtime == nil {
+		return nil, fmt.Errorf("service %s missing runtime", s.Name)
+	}
+
+	service := cloneOf(*s)
+	ps := &PrivateService{
+		Name:                    service.Name,
+		Runtime:                 *service.Runtime,
+		StartCommand:            service.StartCommand,
+		Plan:                    service.Plan,
+		Region:                  service.Region,
+		Git:                     gitConfigOf(service),
+		Build:                   buildConfigOf(service),
+		Docker:                  dockerConfigOf(service),
+		Preview:                 previewConfigOf(service),
+		EnvVars:                 service.EnvVars,
+		MaxShutdownDelaySeconds: service.MaxShutdownDelaySeconds,
+		Disk:                    service.Disk,
+		Tags:                    service.Tags,
+	}
+	return ps, checkConversion(s, ps)
+}
+
+// AsCronJob converts a generic cron service back into a CronJob builder
+func (s *Service) AsCronJob() (*CronJob, error) {
+	if s.Type != ServiceTypeCron {
+		return nil, fmt.Errorf("service %s is not a cron job", s.Name)
+	}
+	if s.Runtime == nil {
+		return nil, fmt.Errorf("service %s missing runtime", s.Name)
+	}
+	if s.Schedule == nil {
+		return nil, fmt.Errorf("cron service %s missing schedule", s.Name)
+	}
+
+	service := cloneOf(*s)
+	cj := &CronJob{
+		Name:         service.Name,
+		Runtime:      *service.Runtime,
+		Schedule:     *service.Schedule,
+		StartCommand: service.StartCommand,
+		Region:       service.Region,
+		Git:          gitConfigOf(service),
+		Build:        buildConfigOf(service),
+		Docker:       dockerConfigOf(service),
+		Preview:      previewConfigOf(service),
+		EnvVars:      service.EnvVars,
+		Tags:         service.Tags,
+	}
+	return cj, checkConversion(s, cj)
+}
+
+// AsStaticSite converts a generic static site back into a StaticSite builder
+func (s *Service) AsStaticSite() (*StaticSite, error) {
+	if !isStaticSite(*s) {
+		return nil, fmt.Errorf("service %s is not a static site", s.Name)
+	}
+
+	service := cloneOf(*s)
+	ss := &StaticSite{
+		Name:    service.Name,
+		Region:  service.Region,
+		Git:     gitConfigOf(service),
+		Build:   buildConfigOf(service),
+		Preview: previewConfigOf(service),
+		Domains: service.Domains,
+		Tags:    service.Tags,
+	}
+	if service.StaticPublishPath != nil || service.Headers != nil || service.Routes != nil {
+		ss.StaticSite = &StaticSiteConfig{
+			Headers: service.Headers,
+			Routes:  service.Routes,
+		}
+		if service.StaticPublishPath != nil {
+			ss.StaticSite.StaticPublishPath = *service.StaticPublishPath
+		}
+	}
+	return ss, checkConversion(s, ss)
+}
+
+// AsKeyValueService converts a generic key-value service back into a KeyValueService builder
+func (s *Service) AsKeyValueService() (*KeyValueService, error) {
+	if s.Type != ServiceTypeKeyValue {
+		return nil, fmt.Errorf("service %s is not a keyvalue service", s.Name)
+	}
+
+	service := cloneOf(*s)
+	kvs := &KeyValueService{
+		Name:    service.Name,
+		Plan:    service.Plan,
+		Region:  service.Region,
+		Preview: previewConfigOf(service),
+		Tags:    service.Tags,
+	}
+	if service.IPAllowList != nil || service.MaxMemoryPolicy != nil {
+		kvs.KeyValue = &KeyValueConfig{
+			IPAllowList:     service.IPAllowList,
+			MaxMemoryPolicy: service.MaxMemoryPolicy,
+		}
+	}
+	return kvs, checkConversion(s, kvs)
+}
+
+// ToBuilders converts every service back into its typed builder
+// Services with the deprecated redis type become KeyValueService builders, which write keyvalue
+func (bp *Blueprint) ToBuilders() ([]ServiceBuilder, error) {
+	builders := make([]ServiceBuilder, 0, len(bp.Services))
+
+	for i := range bp.Services {
+		service := &bp.Services[i]
+
+		var builder ServiceBuilder
+		var err error
+		switch {
+		case isStaticSite(*service):
+			builder, err = service.AsStaticSite()
+		case service.Type == ServiceTypeWeb:
+			builder, err = service.AsWebService()
+		case service.Type == ServiceTypeWorker:
+			builder, err = service.AsBackgroundWorker()
+		case service.Type == ServiceTypePServ:
+			builder, err = service.AsPrivateService()
+		case service.Type == ServiceTypeCron:
+			builder, err = service.AsCronJob()
+		case service.Type == ServiceTypeKeyValue || service.Type == ServiceTypeRedis:
+			normalized := *service
+			normalized.NormalizeKeyValueType()
+			builder, err = normalized.AsKeyValueService()
+		default:
+			return nil, fmt.Errorf("service %s has unknown type %q", service.Name, service.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		builders = append(builders, builder)
+	}
+
+	return builders, nil
+}
+
+// checkConversion fails when a builder cannot represent every field of the original service
+func checkConversion(original *Service, builder ServiceBuilder) error {
+	if !valuesEqual(reflect.ValueOf(*original), reflect.ValueOf(*builder.ToService())) {
+		return fmt.Errorf("service %s has fields not supported by %T", original.Name, builder)
+	}
+	return nil
+}
+
+// gitConfigOf extracts the Git configuration group from a service
+func gitConfigOf(s Service) *GitConfig {
+	if s.Repo == nil && s.Branch == nil {
+		return nil
+	}
+	return &GitConfig{Repo: s.Repo, Branch: s.Branch}
+}
+
+// buildConfigOf extracts the build configuration group from a service
+func buildConfigOf(s Service) *BuildConfig {
+	if s.BuildCommand == nil && s.PreDeployCommand == nil && s.BuildFilter == nil && s.RootDir == nil && s.AutoDeploy == nil {
+		return nil
+	}
+	return &BuildConfig{
+		BuildCommand:     s.BuildCommand,
+		PreDeployCommand: s.PreDeployCommand,
+		BuildFilter:      s.BuildFilter,
+		RootDir:          s.RootDir,
+		AutoDeploy:       s.AutoDeploy,
+	}
+}
+
+// dockerConfigOf extracts the Docker configuration group from a service
+func dockerConfigOf(s Service) *DockerConfig {
+	if s.DockerCommand == nil && s.DockerfilePath == nil && s.DockerContext == nil && s.Image == nil && s.RegistryCredential == nil {
+		return nil
+	}
+	return &DockerConfig{
+		DockerCommand:      s.DockerCommand,
+		DockerfilePath:     s.DockerfilePath,
+		DockerContext:      s.DockerContext,
+		Image:              s.Image,
+		RegistryCredential: s.RegistryCredential,
+	}
+}
+
+// scalingConfigOf extracts the scaling configuration group from a service
+func scalingConfigOf(s Service) *ScalingConfig {
+	if s.NumInstances == nil && s.Scaling == nil {
+		return nil
+	}
+	return &ScalingConfig{NumInstances: s.NumInstances, Scaling: s.Scaling}
+}
+
+// previewConfigOf extracts the preview configuration group from a service
+func previewConfigOf(s Service) *PreviewConfig {
+	if s.Previews == nil && s.PreviewPlan == nil {
+		return nil
+	}
+	return &PreviewConfig{Previews: s.Previews, PreviewPlan: s.PreviewPlan}
+}
+
+// ClassifyServices splits the blueprint's services into static sites, reconstructed
+// with their StaticSiteConfig grouping, and all other services
+// Loaded static sites arrive as generic web services with runtime static; this
+// recovers the StaticSite form so headers and routes can be edited where they belong
+func (bp *Blueprint) ClassifyServices() (staticSites []*StaticSite, services []Service, err error) {
+	for i := range bp.Services {
+		service := &bp.Services[i]
+		if !isStaticSite(*service) {
+			services = append(services, *service)
+			continue
+		}
+
+		ss, err := service.AsStaticSite()
+		if err != nil {
+			return nil, nil, err
+		}
+		staticSites = append(staticSites, ss)
+	}
+
+	return staticSites, services, nil
+}
@@ -0,0 +1,174 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToBuildersRoundTrip(t *testing.T) {
+	bp := NewBlueprint().WithServices(
+		NewWebService("api", RuntimeNode).
+			WithGit("https://github.com/example/app", "main").
+			WithBuild("npm ci").
+			WithStartCommand("npm start").
+			WithHealthCheck("/health").
+			WithDomains("api.example.com").
+			WithAutoScaling(1, 3, 70).
+			WithEnv("NODE_ENV", "production").
+			WithDisk("data", "/var/data", 10),
+		NewBackgroundWorker("worker", RuntimePython).
+			WithStartCommand("python worker.py").
+			WithPreviewEnvironment(PreviewGenerationAutomatic, PlanStarter),
+		NewPrivateService("internal", RuntimeDocker).WithPlan(PlanStandard),
+		NewCronJob("cleanup", RuntimeGo, "0 3 * * *").WithStartCommand("./cleanup"),
+		NewStaticSite("frontend").
+			WithPublishPath("./dist").
+			WithRoutes(Route{Type: string(RouteTypeRewrite), Source: "/*", Destination: "/index.html"}),
+		NewKeyValueService("cache").WithMaxMemoryPolicy(MaxMemoryPolicyAllKeysLRU).WithPublicAccess(),
+	)
+
+	builders, err := bp.ToBuilders()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedTypes := []string{"*render.WebService", "*render.BackgroundWorker", "*render.PrivateService",
+		"*render.CronJob", "*render.StaticSite", "*render.KeyValueService"}
+	for i, builder := range builders {
+		if got := fmt.Sprintf("%T", builder); got != expectedTypes[i] {
+			t.Errorf("builder %d: expected %s, got %s", i, expectedTypes[i], got)
+		}
+	}
+
+	rebuilt := NewBlueprint().WithServices(builders...)
+	if !bp.Equal(rebuilt) {
+		t.Errorf("round trip changed the blueprint:\n%+v\n%+v", bp.Services, rebuilt.Services)
+	}
+
+	// Builders are independent of the original blueprint
+	builders[0].(*WebService).WithDomains("other.example.com")
+	if len(bp.Services[0].Domains) != 1 {
+		t.Errorf("expected original domains to be unchanged, got %v", bp.Services[0].Domains)
+	}
+
+	// The deprecated redis type converts as keyvalue
+	legacy, err := LoadFromString("services:\n  - name: cache\n    type: redis\n    plan: starter\n    ipAllowList: []\n    maxmemoryPolicy: noeviction\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	legacyBuilders, err := legacy.ToBuilders()
+	if err != nil {
+		t.Fatalf("unexpected error for a redis service: %v", err)
+	}
+	if got := fmt.Sprintf("%T", legacyBuilders[0]); got != "*render.KeyValueService" {
+		t.Errorf("expected *render.KeyValueService for redis, got %s", got)
+	}
+	normalized := CopyBlueprint(legacy)
+	normalized.NormalizeKeyValueTypes()
+	if rebuilt := NewBlueprint().WithServices(legacyBuilders...); !normalized.Equal(rebuilt) {
+		t.Errorf("expected the redis service to round trip as keyvalue:\n%+v\n%+v", normalized.Services, rebuilt.Services)
+	}
+}
+
+func TestToBuildersErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  Service
+		expected string
+	}{
+		{
+			name:     "unknown type",
+			service:  Service{Name: "fn", Type: ServiceType("lambda")},
+			expected: `service fn has unknown type "lambda"`,
+		},
+		{
+			name: "field the builder cannot hold",
+			service: Service{
+				Name: "worker", Type: ServiceTypeWorker, Runtime: runtimePtr(RuntimeNode),
+				Domains: []string{"worker.example.com"},
+			},
+			expected: "service worker has fields not supported by *render.BackgroundWorker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := &Blueprint{Services: []Service{tt.service}}
+			_, err := bp.ToBuilders()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestStaticSiteLoadSaveRoundTrip(t *testing.T) {
+	const content = `services:
+  - name: api
+    type: web
+    runtime: node
+    startCommand: npm start
+  - name: frontend
+    type: web
+    runtime: static
+    buildCommand: npm run build
+    staticPublishPath: ./dist
+    headers:
+      - path: /*
+        name: X-Frame-Options
+        value: DENY
+      - path: /assets/*
+        name: Cache-Control
+        value: public, max-age=31536000
+    routes:
+      - type: rewrite
+        source: /app/*
+        destination: /index.html
+      - type: redirect
+        source: /old
+        destination: /new
+`
+
+	loaded, err := LoadFromString(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saved, err := loaded.ToYAMLBytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reloaded, err := LoadFromBytes(saved)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reloaded.Equal(loaded) {
+		t.Errorf("expected a lossless load/save cycle, got:\n%s", saved)
+	}
+
+	staticSites, services, err := reloaded.ClassifyServices()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(staticSites) != 1 || len(services) != 1 || services[0].Name != "api" {
+		t.Fatalf("expected one static site and the api service, got %d static sites and %+v", len(staticSites), services)
+	}
+
+	site := staticSites[0]
+	if site.Name != "frontend" || site.StaticSite == nil || site.StaticSite.StaticPublishPath != "./dist" {
+		t.Fatalf("expected the frontend static site config, got %+v", site)
+	}
+	if !reflect.DeepEqual(site.StaticSite.Headers, loaded.Services[1].Headers) {
+		t.Errorf("expected headers %+v, got %+v", loaded.Services[1].Headers, site.StaticSite.Headers)
+	}
+	if !reflect.DeepEqual(site.StaticSite.Routes, loaded.Services[1].Routes) {
+		t.Errorf("expected routes %+v, got %+v", loaded.Services[1].Routes, site.StaticSite.Routes)
+	}
+
+	// The reconstructed builder produces the same service
+	if rebuilt := site.ToService(); !valuesEqual(reflect.ValueOf(*rebuilt), reflect.ValueOf(loaded.Services[1])) {
+		t.Errorf("expected the static site to rebuild the loaded service, got %+v", rebuilt)
+	}
+}
@@ -0,0 +1,139 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMinInterval returns the shortest gap between two runs of a five-field cron schedule
+// The day fields only ever widen the gap, so just minute and hour are expanded
+func cronMinInterval(schedule string) (time.Duration, bool) {
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return 0, false
+	}
+
+	minutes, ok := expandCronField(fields[0], 0, 59)
+	if !ok {
+		return 0, false
+	}
+	hours, ok := expandCronField(fields[1], 0, 23)
+	if !ok {
+		return 0, false
+	}
+
+	// Minutes of the day at which the schedule fires, in ascending order
+	var runs []int
+	for _, h := range hours {
+		for _, m := range minutes {
+			runs = append(runs, h*60+m)
+		}
+	}
+
+	gap := 24 * 60
+	for i := 1; i < len(runs); i++ {
+		if d := runs[i] - runs[i-1]; d < gap {
+			gap = d
+		}
+	}
+	// The last run of one day is followed by the first run of the next only when every day matches
+	if fields[2] == "*" && fields[4] == "*" {
+		if d := runs[0] + 24*60 - runs[len(runs)-1]; d < gap {
+			gap = d
+		}
+	}
+
+	return time.Duration(gap) * time.Minute, true
+}
+
+// expandCronField returns the sorted values matched by a numeric cron field
+func expandCronField(field string, min, max int) ([]int, bool) {
+	matched := make([]bool, max+1)
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, false
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil {
+				return nil, false
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, false
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, false
+		}
+
+		for v := lo; v <= hi; v += step {
+			matched[v] = true
+		}
+	}
+
+	var values []int
+	for v := min; v <= max; v++ {
+		if matched[v] {
+			values = append(values, v)
+		}
+	}
+	return values, len(values) > 0
+}
+
+// EveryNMinutes returns a schedule that runs every n minutes, for n from 1 to 59
+func EveryNMinutes(n int) (string, error) {
+	if n < 1 || n > 59 {
+		return "", fmt.Errorf("cron interval %d minutes out of range 1-59", n)
+	}
+	return fmt.Sprintf("*/%d * * * *", n), nil
+}
+
+// Daily returns a schedule that runs once a day at hour:minute UTC
+// CronJob.WithDailySchedule records the same range errors on the builder instead
+func Daily(hour, minute int) (string, error) {
+	return dailyCron(hour, minute)
+}
+
+// Weekly returns a schedule that runs once a week on day at hour:minute UTC
+func Weekly(day time.Weekday, hour, minute int) (string, error) {
+	if day < time.Sunday || day > time.Saturday {
+		return "", fmt.Errorf("cron weekday %d out of range", day)
+	}
+	schedule, err := dailyCron(hour, minute)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(schedule, "*") + strconv.Itoa(int(day)), nil
+}
+
+// dailyCron builds a once-a-day schedule after checking the time of day
+func dailyCron(hour, minute int) (string, error) {
+	if hour < 0 || hour > 23 {
+		return "", fmt.Errorf("cron hour %d out of range 0-23", hour)
+	}
+	if minute < 0 || minute > 59 {
+		return "", fmt.Errorf("cron minute %d out of range 0-59", minute)
+	}
+	return fmt.Sprintf("%d %d * * *", minute, hour), nil
+}
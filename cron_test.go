@@ -0,0 +1,71 @@
+package render
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronHelpers(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func() (string, error)
+		expected string
+	}{
+		{"every 15 minutes", func() (string, error) { return EveryNMinutes(15) }, "*/15 * * * *"},
+		{"daily", func() (string, error) { return Daily(3, 30) }, "30 3 * * *"},
+		{"weekly", func() (string, error) { return Weekly(time.Monday, 9, 0) }, "0 9 * * 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := tt.fn()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if schedule != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, schedule)
+			}
+		})
+	}
+
+	schedule, _ := EveryNMinutes(15)
+	if interval, ok := cronMinInterval(schedule); !ok || interval != 15*time.Minute {
+		t.Errorf("expected a 15m interval, got %v (%v)", interval, ok)
+	}
+}
+
+func TestCronHelpersRangeValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (string, error)
+	}{
+		{"zero minute interval", func() (string, error) { return EveryNMinutes(0) }},
+		{"hour interval", func() (string, error) { return EveryNMinutes(60) }},
+		{"daily hour", func() (string, error) { return Daily(24, 0) }},
+		{"daily minute", func() (string, error) { return Daily(0, 60) }},
+		{"weekly negative hour", func() (string, error) { return Weekly(time.Friday, -1, 0) }},
+		{"weekly day", func() (string, error) { return Weekly(time.Weekday(7), 0, 0) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := tt.fn()
+			if err == nil {
+				t.Errorf("expected an error for an out-of-range value, got %q", schedule)
+			}
+			if schedule != "" {
+				t.Errorf("expected an empty schedule alongside the error, got %q", schedule)
+			}
+		})
+	}
+
+	cj := NewCronJob("nightly", RuntimeNode, "").WithDailySchedule(2, 15)
+	if cj.Schedule != "15 2 * * *" || len(cj.Errors()) != 0 {
+		t.Errorf("expected schedule %q without errors, got %q %v", "15 2 * * *", cj.Schedule, cj.Errors())
+	}
+
+	invalid := NewCronJob("nightly", RuntimeNode, "0 0 * * *").WithDailySchedule(25, 0)
+	if len(invalid.Errors()) != 1 || invalid.Schedule != "0 0 * * *" {
+		t.Errorf("expected one error and the schedule unchanged, got %q %v", invalid.Schedule, invalid.Errors())
+	}
+}
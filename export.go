@@ -0,0 +1,64 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resource is a flat, format-neutral description of one blueprint resource
+// Attributes use render.yaml field names and exclude the name
+type Resource struct {
+	Kind       ResourceKind
+	Name       string
+	Attributes map[string]interface{}
+}
+
+// ToResourceList returns one Resource per service, database and environment group
+// It stops at the first resource that cannot be converted and returns its error
+func (bp *Blueprint) ToResourceList() ([]Resource, error) {
+	var resources []Resource
+
+	if bp == nil {
+		return resources, nil
+	}
+
+	for _, service := range bp.Services {
+		value, err := marshalServiceYAML(service, DefaultMarshalOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal service %s: %w", service.Name, err)
+		}
+		resource, err := newResource(ResourceKindService, service.Name, value)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	for _, db := range bp.Databases {
+		resource, err := newResource(ResourceKindDatabase, db.Name, db)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	for _, group := range bp.EnvVarGroups {
+		resource, err := newResource(ResourceKindEnvVarGroup, group.Name, group)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// newResource builds a Resource from the YAML form of value
+func newResource(kind ResourceKind, name string, value interface{}) (Resource, error) {
+	attributes, err := toYAMLMap(value)
+	if err != nil {
+		return Resource{}, fmt.Errorf("failed to convert %s %s: %w", kind, name, err)
+	}
+	restoreEmptySlices(attributes, reflect.ValueOf(value), false)
+	delete(attributes, "name")
+
+	return Resource{Kind: kind, Name: name, Attributes: attributes}, nil
+}
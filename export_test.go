@@ -0,0 +1,83 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToResourceList(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).WithPlan(PlanStandard).WithDomains("api.example.com")).
+		WithDatabases(NewDatabase("main-db").WithPlan(PlanBasic1GB).WithPostgreSQL(PostgreSQL16)).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("LOG_LEVEL", "info"))
+
+	resources, err := bp.ToResourceList()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 3 {
+		t.Fatalf("expected 3 resources, got %d", len(resources))
+	}
+
+	tests := []struct {
+		resource   Resource
+		kind       ResourceKind
+		name       string
+		attributes map[string]interface{}
+	}{
+		{
+			resource: resources[0],
+			kind:     ResourceKindService,
+			name:     "api",
+			attributes: map[string]interface{}{
+				"type":    "web",
+				"runtime": "node",
+				"plan":    "standard",
+				"domains": []interface{}{"api.example.com"},
+			},
+		},
+		{
+			resource: resources[1],
+			kind:     ResourceKindDatabase,
+			name:     "main-db",
+			attributes: map[string]interface{}{
+				"plan":                 "basic-1gb",
+				"postgresMajorVersion": "16",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.resource.Kind != tt.kind || tt.resource.Name != tt.name {
+				t.Errorf("expected %s %s, got %s %s", tt.kind, tt.name, tt.resource.Kind, tt.resource.Name)
+			}
+			for key, expected := range tt.attributes {
+				if got := tt.resource.Attributes[key]; !reflect.DeepEqual(got, expected) {
+					t.Errorf("expected attribute %s = %v, got %v", key, expected, got)
+				}
+			}
+			if _, exists := tt.resource.Attributes["name"]; exists {
+				t.Errorf("expected name to be excluded from attributes")
+			}
+		})
+	}
+
+	if resources[2].Kind != ResourceKindEnvVarGroup || resources[2].Name != "shared" {
+		t.Errorf("expected the shared environment group, got %s %s", resources[2].Kind, resources[2].Name)
+	}
+
+	// A private database keeps its deny-all allow list
+	private, err := NewBlueprint().WithDatabases(NewDatabase("main-db").WithPrivateAccess()).ToResourceList()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := private[0].Attributes["ipAllowList"]; !reflect.DeepEqual(got, []interface{}{}) {
+		t.Errorf("expected an empty ipAllowList attribute, got %#v", got)
+	}
+
+	var empty *Blueprint
+	if resources, err := empty.ToResourceList(); err != nil || len(resources) != 0 {
+		t.Errorf("expected no resources and no error for a nil blueprint, got %v %v", resources, err)
+	}
+}
@@ -1,9 +1,14 @@
 package render
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -11,15 +16,51 @@ import (
 
 // WriteToFile writes the blueprint to a YAML file
 func (bp *Blueprint) WriteToFile(path string) error {
+	return bp.WriteToFileWithOptions(path, ValidationOptions{})
+}
+
+// WriteToFileWithOptions writes the blueprint to a YAML file, validating it with opts first
+// Set opts.RejectDeprecated to refuse blueprints that still use the redis service type
+func (bp *Blueprint) WriteToFileWithOptions(path string, opts ValidationOptions) error {
 	if bp == nil {
 		return fmt.Errorf("blueprint is nil")
 	}
 
 	// Validate blueprint before writing
-	if errors := ValidateBlueprint(bp); len(errors) > 0 {
+	var errors []string
+	for _, issue := range ValidateBlueprintWithOptions(bp, opts) {
+		if issue.Severity == SeverityError {
+			errors = append(errors, issue.Message)
+		}
+	}
+	if len(errors) > 0 {
 		return fmt.Errorf("blueprint validation failed: %s", strings.Join(errors, "; "))
 	}
 
+	return bp.writeYAMLFile(path)
+}
+
+// ValidateAndWrite validates the blueprint and writes it to a YAML file
+// unless there are error-severity issues; warnings do not block the write
+// All issues are returned so callers can report them
+func (bp *Blueprint) ValidateAndWrite(path string) ([]ValidationIssue, error) {
+	issues := ValidateBlueprintDetailed(bp)
+
+	errorCount := 0
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return issues, fmt.Errorf("blueprint validation failed with %d error(s)", errorCount)
+	}
+
+	return issues, bp.writeYAMLFile(path)
+}
+
+// writeYAMLFile writes the blueprint to path without validating it
+func (bp *Blueprint) writeYAMLFile(path string) error {
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -50,6 +91,48 @@ func (bp *Blueprint) WriteRenderYAMLTo(dir string) error {
 	return bp.WriteToFile(filepath.Join(dir, "render.yaml"))
 }
 
+// Format selects the output format for Write
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// Write writes the blueprint to w in the given format
+// JSON output uses the same keys as render.yaml
+func (bp *Blueprint) Write(w io.Writer, format Format) error {
+	if bp == nil {
+		return fmt.Errorf("blueprint is nil")
+	}
+
+	switch format {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		if err := encoder.Encode(bp); err != nil {
+			return fmt.Errorf("failed to marshal blueprint to YAML: %w", err)
+		}
+		return encoder.Close()
+	case FormatJSON:
+		data, err := bp.ToYAMLBytes()
+		if err != nil {
+			return err
+		}
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("failed to convert blueprint to JSON: %w", err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(document); err != nil {
+			return fmt.Errorf("failed to marshal blueprint to JSON: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
 // ToYAMLString converts the blueprint to a YAML string
 func (bp *Blueprint) ToYAMLString() (string, error) {
 	if bp == nil {
@@ -64,6 +147,89 @@ func (bp *Blueprint) ToYAMLString() (string, error) {
 	return string(data), nil
 }
 
+// ServiceYAML returns the YAML for a single named service, shaped as in the full blueprint
+func (bp *Blueprint) ServiceYAML(name string) (string, error) {
+	service := bp.FindService(name)
+	if service == nil {
+		return "", fmt.Errorf("service %s not found", name)
+	}
+
+	value, err := marshalServiceYAML(*service, DefaultMarshalOptions())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal service %s to YAML: %w", name, err)
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal service %s to YAML: %w", name, err)
+	}
+
+	return string(data), nil
+}
+
+// RedactedValue replaces secret values in redacted output
+const RedactedValue = "***REDACTED***"
+
+// secretKeyMarkers are key fragments that suggest an env var holds a secret
+var secretKeyMarkers = []string{"SECRET", "PASSWORD", "PASSWD", "TOKEN", "API_KEY", "APIKEY", "PRIVATE_KEY", "CREDENTIAL"}
+
+// ToRedactedYAMLString converts the blueprint to a YAML string that is safe to log
+// Values of secret env vars (sync: false, or keys that look like secrets) are masked;
+// the blueprint itself is left unchanged
+func (bp *Blueprint) ToRedactedYAMLString() (string, error) {
+	if bp == nil {
+		return "", fmt.Errorf("blueprint is nil")
+	}
+
+	redacted := CopyBlueprint(bp)
+	redactEnvVars := func(envVars []EnvVar) {
+		for i := range envVars {
+			if envVars[i].Value != nil && isSecretEnvVar(envVars[i]) {
+				value := RedactedValue
+				envVars[i].Value = &value
+			}
+		}
+	}
+	for i := range redacted.Services {
+		redactEnvVars(redacted.Services[i].EnvVars)
+	}
+	for i := range redacted.EnvVarGroups {
+		redactEnvVars(redacted.EnvVarGroups[i].EnvVars)
+	}
+
+	return redacted.ToYAMLString()
+}
+
+// StripSecretValues returns a copy of the blueprint that is safe to commit
+// Env vars with sync: false lose their value but keep their key and sync flag,
+// so operators supply the value at deploy time; other values are kept
+func (bp *Blueprint) StripSecretValues() *Blueprint {
+	return bp.MapEnvVars(func(envVar EnvVar) EnvVar {
+		if envVar.Sync != nil && !*envVar.Sync {
+			envVar.Value = nil
+		}
+		return envVar
+	})
+}
+
+// isSecretEnvVar reports whether an env var is marked or named like a secret
+func isSecretEnvVar(envVar EnvVar) bool {
+	if envVar.Sync != nil && !*envVar.Sync {
+		return true
+	}
+	if envVar.Key == nil {
+		return false
+	}
+
+	key := strings.ToUpper(*envVar.Key)
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
 // ToYAMLBytes converts the blueprint to YAML bytes
 func (bp *Blueprint) ToYAMLBytes() ([]byte, error) {
 	if bp == nil {
@@ -78,16 +244,255 @@ func (bp *Blueprint) ToYAMLBytes() ([]byte, error) {
 	return data, nil
 }
 
+// ToYAMLStringWithOptions converts the blueprint to a YAML string using the given marshal options
+func (bp *Blueprint) ToYAMLStringWithOptions(opts MarshalOptions) (string, error) {
+	data, err := bp.ToYAMLBytesWithOptions(opts)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// ToYAMLBytesWithOptions converts the blueprint to YAML bytes using the given marshal options
+func (bp *Blueprint) ToYAMLBytesWithOptions(opts MarshalOptions) ([]byte, error) {
+	if bp == nil {
+		return nil, fmt.Errorf("blueprint is nil")
+	}
+
+	value, err := bp.marshalYAMLWithOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blueprint to YAML: %w", err)
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blueprint to YAML: %w", err)
+	}
+
+	return data, nil
+}
+
+// ToYAMLWithAnchors converts the blueprint to a YAML string, emitting env var lists shared
+// by several services once under an anchor and aliasing the later copies
+func (bp *Blueprint) ToYAMLWithAnchors() (string, error) {
+	if bp == nil {
+		return "", fmt.Errorf("blueprint is nil")
+	}
+
+	var root yaml.Node
+	if err := root.Encode(bp); err != nil {
+		return "", fmt.Errorf("failed to marshal blueprint to YAML: %w", err)
+	}
+
+	if services := mappingValue(&root, "services"); services != nil {
+		var seen []*yaml.Node
+		var seenKeys []string
+		for _, service := range services.Content {
+			index := mappingIndex(service, "envVars")
+			if index < 0 || len(service.Content[index].Content) == 0 {
+				continue
+			}
+			envVars := service.Content[index]
+
+			data, err := yaml.Marshal(envVars)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal blueprint to YAML: %w", err)
+			}
+			key := string(data)
+
+			shared := -1
+			for i, seenKey := range seenKeys {
+				if seenKey == key {
+					shared = i
+					break
+				}
+			}
+			if shared < 0 {
+				seen = append(seen, envVars)
+				seenKeys = append(seenKeys, key)
+				continue
+			}
+
+			anchor := seen[shared]
+			if anchor.Anchor == "" {
+				anchor.Anchor = fmt.Sprintf("envVars%d", shared+1)
+			}
+			service.Content[index] = &yaml.Node{Kind: yaml.AliasNode, Value: anchor.Anchor, Alias: anchor}
+		}
+	}
+
+	data, err := yaml.Marshal(&root)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal blueprint to YAML: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// mappingIndex returns the index of the value for key in a mapping node, or -1
+func mappingIndex(node *yaml.Node, key string) int {
+	if node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if index := mappingIndex(node, key); index >= 0 {
+		return node.Content[index]
+	}
+	return nil
+}
+
+// LoadOptions configures LoadFromFileOptions and LoadFromBytesOptions
+// The zero value matches LoadFromFile
+type LoadOptions struct {
+	// NormalizeDeprecated rewrites deprecated values on load, such as redis services to keyvalue
+	NormalizeDeprecated bool
+	// Strict rejects fields that are not part of the blueprint format
+	Strict bool
+	// ResolveIncludes loads the files listed under x-include, relative to the including
+	// file, and merges them ahead of it
+	// A file reached through several includes contributes its resources once, since
+	// identical same-named resources are collapsed; differing ones still conflict
+	ResolveIncludes bool
+}
+
+// IncludeKey is the extension key listing blueprint files to include
+const IncludeKey = "x-include"
+
 // LoadFromFile loads a blueprint from a YAML file
 func LoadFromFile(path string) (*Blueprint, error) {
+	return LoadFromFileOptions(path, LoadOptions{})
+}
+
+// LoadFromFileOptions loads a blueprint from a YAML file using the given options
+func LoadFromFileOptions(path string, opts LoadOptions) (*Blueprint, error) {
+	var bp *Blueprint
+	var err error
+	if opts.ResolveIncludes {
+		bp, err = loadWithIncludes(path, opts, nil)
+	} else {
+		bp, _, err = decodeBlueprintFile(path, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.NormalizeDeprecated {
+		bp.NormalizeKeyValueTypes()
+	}
+
+	return bp, nil
+}
+
+// loadWithIncludes loads path and everything it includes, merged in include order
+// stack holds the files currently being loaded so cycles can be reported
+func loadWithIncludes(path string, opts LoadOptions, stack []string) (*Blueprint, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	for _, ancestor := range stack {
+		if ancestor == absPath {
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(append(stack, absPath), " -> "))
+		}
+	}
+	stack = append(stack, absPath)
+
+	bp, includes, err := decodeBlueprintFile(absPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *Blueprint
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(absPath), include)
+		}
+		included, err := loadWithIncludes(include, opts, stack)
+		if err != nil {
+			return nil, err
+		}
+		if merged, err = MergeBlueprintsWithStrategy(merged, included, MergeStrategyDedupIdentical); err != nil {
+			return nil, fmt.Errorf("failed to merge %s into %s: %w", include, path, err)
+		}
+	}
+	if merged == nil {
+		return bp, nil
+	}
+
+	if merged, err = MergeBlueprintsWithStrategy(merged, bp, MergeStrategyDedupIdentical); err != nil {
+		return nil, fmt.Errorf("failed to merge includes of %s: %w", path, err)
+	}
+	return merged, nil
+}
+
+// decodeBlueprintFile reads a single blueprint file without resolving includes
+// When opts.ResolveIncludes is set, the x-include list is stripped and returned
+func decodeBlueprintFile(path string, opts LoadOptions) (*Blueprint, []string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		return nil, nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	var includes []string
+	if opts.ResolveIncludes {
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal YAML from %s: %w", path, err)
+		}
+		if len(root.Content) > 0 {
+			document := root.Content[0]
+			if index := mappingIndex(document, IncludeKey); index >= 0 {
+				if err := document.Content[index].Decode(&includes); err != nil {
+					return nil, nil, fmt.Errorf("invalid %s in %s: %w", IncludeKey, path, err)
+				}
+				document.Content = append(document.Content[:index-1], document.Content[index+1:]...)
+				if data, err = yaml.Marshal(&root); err != nil {
+					return nil, nil, fmt.Errorf("failed to strip %s from %s: %w", IncludeKey, path, err)
+				}
+			}
+		}
+	}
+
+	bp, err := LoadFromBytesOptions(data, LoadOptions{Strict: opts.Strict})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load %s: %w", path, err)
 	}
 
+	return bp, includes, nil
+}
+
+// LoadFromBytes parses a blueprint from in-memory YAML
+func LoadFromBytes(data []byte) (*Blueprint, error) {
+	return LoadFromBytesOptions(data, LoadOptions{})
+}
+
+// LoadFromString parses a blueprint from a YAML string
+func LoadFromString(s string) (*Blueprint, error) {
+	return LoadFromBytes([]byte(s))
+}
+
+// LoadFromBytesOptions parses a blueprint from in-memory YAML using the given options
+// ResolveIncludes is ignored since includes are resolved relative to a file
+func LoadFromBytesOptions(data []byte, opts LoadOptions) (*Blueprint, error) {
 	var bp Blueprint
-	if err := yaml.Unmarshal(data, &bp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal YAML from %s: %w", path, err)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(opts.Strict)
+	if err := decoder.Decode(&bp); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+
+	if opts.NormalizeDeprecated {
+		bp.NormalizeKeyValueTypes()
 	}
 
 	return &bp, nil
@@ -103,6 +508,26 @@ func LoadRenderYAMLFrom(dir string) (*Blueprint, error) {
 	return LoadFromFile(filepath.Join(dir, "render.yaml"))
 }
 
+// ParseEach decodes blueprints one document at a time from a multi-document YAML stream
+// fn is called for each document; decoding stops at the first error
+func ParseEach(r io.Reader, fn func(*Blueprint) error) error {
+	decoder := yaml.NewDecoder(r)
+
+	for index := 0; ; index++ {
+		var bp Blueprint
+		if err := decoder.Decode(&bp); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to unmarshal YAML document %d: %w", index, err)
+		}
+
+		if err := fn(&bp); err != nil {
+			return err
+		}
+	}
+}
+
 // WriteWithBackup writes the blueprint to a file, creating a backup if the file exists
 func (bp *Blueprint) WriteWithBackup(path string) error {
 	// Create backup if file exists
@@ -123,4 +548,118 @@ func copyFile(src, dst string) error {
 		return err
 	}
 	return os.WriteFile(dst, data, 0644)
-}
\ No newline at end of file
+}
+
+// envKeyPattern matches valid environment variable names
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseEnvFile reads a .env file and returns its entries as literal environment variables
+func ParseEnvFile(path string) ([]EnvVar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file %s: %w", path, err)
+	}
+
+	envVars, err := parseDotEnv(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse env file %s: %w", path, err)
+	}
+
+	return envVars, nil
+}
+
+// LoadEnvInto adds the entries of a .env file to a service builder's environment variables
+func LoadEnvInto(svc ServiceBuilder, path string) error {
+	appender, ok := svc.(envVarAppender)
+	if !ok {
+		return fmt.Errorf("service builder %T does not support environment variables", svc)
+	}
+
+	envVars, err := ParseEnvFile(path)
+	if err != nil {
+		return err
+	}
+
+	appender.appendEnvVars(envVars...)
+	return nil
+}
+
+// parseDotEnv parses KEY=VALUE lines, skipping blank lines and comments
+// Supports an optional "export " prefix, single-quoted literal values,
+// double-quoted values with escapes, and trailing comments on unquoted values
+func parseDotEnv(data []byte) ([]EnvVar, error) {
+	var envVars []EnvVar
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rawValue, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNum)
+		}
+		key = strings.TrimSpace(key)
+		if !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid key %q", lineNum, key)
+		}
+
+		value, err := parseDotEnvValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		envVars = append(envVars, Env(key, value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return envVars, nil
+}
+
+// parseDotEnvValue unquotes a single .env value
+func parseDotEnvValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single-quoted value")
+		}
+		return raw[1 : end+1], nil
+	case strings.HasPrefix(raw, `"`):
+		var value strings.Builder
+		for i := 1; i < len(raw); i++ {
+			switch raw[i] {
+			case '"':
+				return value.String(), nil
+			case '\\':
+				if i+1 >= len(raw) {
+					return "", fmt.Errorf("unterminated double-quoted value")
+				}
+				i++
+				switch raw[i] {
+				case 'n':
+					value.WriteByte('\n')
+				case 't':
+					value.WriteByte('\t')
+				default:
+					value.WriteByte(raw[i])
+				}
+			default:
+				value.WriteByte(raw[i])
+			}
+		}
+		return "", fmt.Errorf("unterminated double-quoted value")
+	default:
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+}
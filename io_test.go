@@ -0,0 +1,540 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestToRedactedYAMLString(t *testing.T) {
+	secret := EnvSecret("STRIPE_KEY")
+	secret.Value = stringPtr("sk_live_123")
+
+	api := NewWebService("api", RuntimeNode).
+		WithEnv("NODE_ENV", "production").
+		WithEnv("DB_PASSWORD", "hunter2").
+		WithEnvVars(secret)
+	shared := NewEnvVarGroup("shared").
+		WithEnv("LOG_LEVEL", "info").
+		WithEnv("GITHUB_TOKEN", "ghp_abc")
+
+	bp := NewBlueprint().WithServices(api).WithEnvVarGroups(shared)
+
+	redacted, err := bp.ToRedactedYAMLString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, secretValue := range []string{"sk_live_123", "hunter2", "ghp_abc"} {
+		if strings.Contains(redacted, secretValue) {
+			t.Errorf("secret value %q leaked into redacted output:\n%s", secretValue, redacted)
+		}
+	}
+	if count := strings.Count(redacted, RedactedValue); count != 3 {
+		t.Errorf("expected 3 redacted values, got %d:\n%s", count, redacted)
+	}
+	for _, literal := range []string{"production", "info"} {
+		if !strings.Contains(redacted, literal) {
+			t.Errorf("literal value %q should not be redacted:\n%s", literal, redacted)
+		}
+	}
+
+	// The blueprint itself is unchanged
+	if *bp.Services[0].EnvVars[1].Value != "hunter2" || *bp.Services[0].EnvVars[2].Value != "sk_live_123" {
+		t.Errorf("redaction modified the original blueprint")
+	}
+	if *bp.EnvVarGroups[0].EnvVars[1].Value != "ghp_abc" {
+		t.Errorf("redaction modified the original env group")
+	}
+}
+
+func TestLoadEnvInto(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := `# Application settings
+NODE_ENV=production
+export PORT=3000
+GREETING="hello world\n"
+PATTERN='a#b $c'
+LOG_LEVEL=info # trailing comment
+
+EMPTY=
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+
+	api := NewWebService("api", RuntimeNode).WithEnv("EXISTING", "1")
+	if err := LoadEnvInto(api, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []EnvVar{
+		Env("EXISTING", "1"),
+		Env("NODE_ENV", "production"),
+		Env("PORT", "3000"),
+		Env("GREETING", "hello world\n"),
+		Env("PATTERN", "a#b $c"),
+		Env("LOG_LEVEL", "info"),
+		Env("EMPTY", ""),
+	}
+	if !reflect.DeepEqual(api.EnvVars, expected) {
+		t.Errorf("env vars mismatch\nExpected: %v\nGot: %v", expected, api.EnvVars)
+	}
+
+	// The panicking form loads the same entries
+	worker := NewBackgroundWorker("worker", RuntimePython).WithEnvFileOrPanic(path)
+	if len(worker.EnvVars) != len(expected)-1 {
+		t.Errorf("expected %d env vars, got %d", len(expected)-1, len(worker.EnvVars))
+	}
+
+	// Errors are reported rather than swallowed
+	if err := LoadEnvInto(api, filepath.Join(t.TempDir(), "missing.env")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+	if err := LoadEnvInto(NewStaticSite("frontend"), path); err == nil {
+		t.Errorf("expected error for builder without env vars")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.env")
+	if err := os.WriteFile(invalid, []byte("NOT A VALID LINE\n"), 0644); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+	if err := LoadEnvInto(api, invalid); err == nil {
+		t.Errorf("expected error for malformed env file")
+	}
+}
+
+func TestParseEach(t *testing.T) {
+	stream := strings.NewReader(`services:
+  - name: api
+    type: web
+    runtime: node
+---
+services:
+  - name: worker
+    type: worker
+    runtime: python
+---
+databases:
+  - name: main-db
+`)
+
+	var names []string
+	err := ParseEach(stream, func(bp *Blueprint) error {
+		services, databases, _ := GetAllResourceNames(bp)
+		names = append(names, append(services, databases...)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"api", "worker", "main-db"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected callback for %v, got %v", expected, names)
+	}
+
+	// The first callback error stops decoding
+	calls := 0
+	stop := errors.New("stop")
+	err = ParseEach(strings.NewReader("services: []\n---\nservices: []\n"), func(bp *Blueprint) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected to stop after one call with %v, got %d calls and %v", stop, calls, err)
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).
+				WithPlan(PlanStarter).
+				WithEnvVars(Env("NODE_ENV", "production"), EnvDatabaseURL("main-db")),
+			NewKeyValueService("cache").WithPublicAccess(),
+		).
+		WithDatabases(NewDatabase("main-db").WithPlan(PlanBasic1GB))
+
+	for _, format := range []Format{FormatYAML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := bp.Write(&buf, format); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var parsed Blueprint
+			if format == FormatJSON {
+				var document map[string]interface{}
+				if err := json.Unmarshal(buf.Bytes(), &document); err != nil {
+					t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+				}
+			}
+			// JSON is a subset of YAML, so both formats load the same way
+			if err := yaml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+				t.Fatalf("failed to parse output: %v\n%s", err, buf.String())
+			}
+			if !bp.Equal(&parsed) {
+				t.Errorf("round trip through %s changed the blueprint:\n%s", format, buf.String())
+			}
+		})
+	}
+
+	if err := bp.Write(&bytes.Buffer{}, Format("toml")); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
+
+func TestServiceYAML(t *testing.T) {
+	bp := NewBlueprint().WithServices(
+		NewWebService("api", RuntimeNode).WithPlan(PlanStarter).WithStartCommand("npm start"),
+		NewStaticSite("frontend").WithPublishPath("./dist").WithBuild("npm run build"),
+	)
+
+	api, err := bp.ServiceYAML("api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var service Service
+	if err := yaml.Unmarshal([]byte(api), &service); err != nil {
+		t.Fatalf("failed to parse service YAML: %v\n%s", err, api)
+	}
+	if !reflect.DeepEqual(service, bp.Services[0]) {
+		t.Errorf("expected %+v, got %+v", bp.Services[0], service)
+	}
+
+	frontend, err := bp.ServiceYAML("frontend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var static map[string]interface{}
+	if err := yaml.Unmarshal([]byte(frontend), &static); err != nil {
+		t.Fatalf("failed to parse static site YAML: %v\n%s", err, frontend)
+	}
+	expected := map[string]interface{}{
+		"name":              "frontend",
+		"type":              "web",
+		"runtime":           "static",
+		"staticPublishPath": "./dist",
+		"buildCommand":      "npm run build",
+	}
+	if !reflect.DeepEqual(static, expected) {
+		t.Errorf("expected static site shape %v, got %v", expected, static)
+	}
+
+	if _, err := bp.ServiceYAML("missing"); err == nil {
+		t.Errorf("expected an error for a missing service")
+	}
+}
+
+func TestToYAMLWithAnchors(t *testing.T) {
+	shared := []EnvVar{EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyConnectionString)}
+	bp := NewBlueprint().WithServices(
+		NewWebService("api", RuntimeNode).WithEnvVars(shared...).WithEnv("LOG_LEVEL", "info"),
+		NewBackgroundWorker("worker", RuntimeNode).WithEnvVars(shared...).WithEnv("LOG_LEVEL", "info"),
+		NewPrivateService("internal", RuntimeNode).WithEnv("LOG_LEVEL", "debug"),
+	)
+
+	output, err := bp.ToYAMLWithAnchors()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(output, "envVars: &envVars1") {
+		t.Errorf("expected the first env var list to be anchored:\n%s", output)
+	}
+	if strings.Count(output, "*envVars1") != 1 {
+		t.Errorf("expected exactly one alias to the shared env vars:\n%s", output)
+	}
+
+	var parsed Blueprint
+	if err := yaml.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("failed to parse anchored YAML: %v\n%s", err, output)
+	}
+	if !bp.Equal(&parsed) {
+		t.Errorf("expected anchored YAML to round trip:\n%s", output)
+	}
+}
+
+func TestLoadFromFileOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "render.yaml")
+	legacy := "services:\n  - name: cache\n    type: redis\n    ipAllowList: []\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     LoadOptions
+		expected ServiceType
+	}{
+		{"without normalization", LoadOptions{}, ServiceTypeRedis},
+		{"with normalization", LoadOptions{NormalizeDeprecated: true}, ServiceTypeKeyValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp, err := LoadFromFileOptions(path, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := bp.Services[0].Type; got != tt.expected {
+				t.Errorf("expected type %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	// LoadFromFile keeps its semantics
+	bp, err := LoadFromFile(path)
+	if err != nil || bp.Services[0].Type != ServiceTypeRedis {
+		t.Errorf("expected LoadFromFile to keep the redis type, got %v, %v", bp, err)
+	}
+
+	t.Run("strict rejects unknown fields", func(t *testing.T) {
+		typo := filepath.Join(t.TempDir(), "render.yaml")
+		if err := os.WriteFile(typo, []byte("services:\n  - name: api\n    type: web\n    runtme: node\n"), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+		if _, err := LoadFromFileOptions(typo, LoadOptions{Strict: true}); err == nil || !strings.Contains(err.Error(), "runtme") {
+			t.Errorf("expected an unknown field error, got %v", err)
+		}
+		if _, err := LoadFromFileOptions(typo, LoadOptions{}); err != nil {
+			t.Errorf("expected lenient load to succeed, got %v", err)
+		}
+	})
+}
+
+func TestValidateAndWrite(t *testing.T) {
+	t.Run("warnings still write", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "render.yaml")
+		bp := NewBlueprint().WithEnvVarGroups(NewEnvVarGroup("shared"))
+
+		issues, err := bp.ValidateAndWrite(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if findIssue(issues, CodeEmptyEnvGroup) == nil {
+			t.Errorf("expected the empty-env-group warning to be returned, got %+v", issues)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected the file to be written: %v", err)
+		}
+	})
+
+	t.Run("errors block the write", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "render.yaml")
+		bp := NewBlueprint().WithServices(
+			NewBackgroundWorker("worker", RuntimeNode),
+			NewBackgroundWorker("worker", RuntimeNode),
+		)
+
+		issues, err := bp.ValidateAndWrite(path)
+		if err == nil {
+			t.Fatal("expected a validation error")
+		}
+		if findIssue(issues, CodeDuplicateServiceName) == nil {
+			t.Errorf("expected the duplicate-service-name error to be returned, got %+v", issues)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected no file to be written, got %v", err)
+		}
+	})
+}
+
+func TestLoadFromFileOptionsIncludes(t *testing.T) {
+	writeFixture := func(t *testing.T, path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create fixture directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+	}
+
+	t.Run("two-level include", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFixture(t, filepath.Join(dir, "render.yaml"), "x-include: [shared/base.yaml]\nservices:\n  - name: api\n    type: web\n    runtime: node\n")
+		writeFixture(t, filepath.Join(dir, "shared", "base.yaml"), "x-include: [db.yaml]\nenvVarGroups:\n  - name: shared\n    envVars:\n      - key: LOG_LEVEL\n        value: info\n")
+		writeFixture(t, filepath.Join(dir, "shared", "db.yaml"), "databases:\n  - name: main-db\n")
+
+		bp, err := LoadFromFileOptions(filepath.Join(dir, "render.yaml"), LoadOptions{ResolveIncludes: true, Strict: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got := []string{bp.Services[0].Name, bp.EnvVarGroups[0].Name, bp.Databases[0].Name}
+		if !reflect.DeepEqual(got, []string{"api", "shared", "main-db"}) {
+			t.Errorf("expected resources from every level, got %v", got)
+		}
+
+		out, err := bp.ToYAMLString()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(out, IncludeKey) {
+			t.Errorf("expected %s to be stripped, got:\n%s", IncludeKey, out)
+		}
+	})
+
+	t.Run("diamond include", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFixture(t, filepath.Join(dir, "render.yaml"), "x-include: [api.yaml, worker.yaml]\n")
+		writeFixture(t, filepath.Join(dir, "api.yaml"), "x-include: [db.yaml]\nservices:\n  - name: api\n    type: web\n    runtime: node\n")
+		writeFixture(t, filepath.Join(dir, "worker.yaml"), "x-include: [db.yaml]\nservices:\n  - name: worker\n    type: worker\n    runtime: node\n")
+		writeFixture(t, filepath.Join(dir, "db.yaml"), "databases:\n  - name: main-db\n    plan: basic-1gb\n")
+
+		bp, err := LoadFromFileOptions(filepath.Join(dir, "render.yaml"), LoadOptions{ResolveIncludes: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(bp.Services) != 2 || len(bp.Databases) != 1 || bp.Databases[0].Name != "main-db" {
+			t.Errorf("expected two services and the shared database once, got %+v %+v", bp.Services, bp.Databases)
+		}
+	})
+
+	t.Run("conflicting includes", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFixture(t, filepath.Join(dir, "render.yaml"), "x-include: [a.yaml, b.yaml]\n")
+		writeFixture(t, filepath.Join(dir, "a.yaml"), "databases:\n  - name: main-db\n    plan: basic-1gb\n")
+		writeFixture(t, filepath.Join(dir, "b.yaml"), "databases:\n  - name: main-db\n    plan: pro-8gb\n")
+
+		_, err := LoadFromFileOptions(filepath.Join(dir, "render.yaml"), LoadOptions{ResolveIncludes: true})
+		if err == nil || !strings.Contains(err.Error(), "main-db") {
+			t.Errorf("expected a conflict on main-db, got %v", err)
+		}
+	})
+
+	t.Run("cyclic include", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFixture(t, filepath.Join(dir, "a.yaml"), "x-include: [b.yaml]\n")
+		writeFixture(t, filepath.Join(dir, "b.yaml"), "x-include: [a.yaml]\n")
+
+		_, err := LoadFromFileOptions(filepath.Join(dir, "a.yaml"), LoadOptions{ResolveIncludes: true})
+		if err == nil || !strings.Contains(err.Error(), "include cycle") {
+			t.Errorf("expected an include cycle error, got %v", err)
+		}
+	})
+}
+
+func TestWriteToFileRejectDeprecated(t *testing.T) {
+	bp := NewBlueprint().WithServices(NewKeyValueService("cache"))
+	bp.Services[0].Type = ServiceTypeRedis
+
+	strict := filepath.Join(t.TempDir(), "render.yaml")
+	err := bp.WriteToFileWithOptions(strict, ValidationOptions{RejectDeprecated: true})
+	if err == nil || !strings.Contains(err.Error(), "use keyvalue") {
+		t.Errorf("expected the strict write to reject redis, got %v", err)
+	}
+	if _, err := os.Stat(strict); !os.IsNotExist(err) {
+		t.Errorf("expected no file from the strict write, got %v", err)
+	}
+
+	lenient := filepath.Join(t.TempDir(), "render.yaml")
+	if err := bp.WriteToFile(lenient); err != nil {
+		t.Errorf("expected the lenient write to succeed, got %v", err)
+	}
+}
+
+func TestLoadFromBytes(t *testing.T) {
+	const content = "services:\n  - name: api\n    type: web\n    runtime: node\n    plan: starter\n"
+
+	bp, err := LoadFromBytes([]byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bp.Services) != 1 || bp.Services[0].Name != "api" || *bp.Services[0].Plan != PlanStarter {
+		t.Errorf("expected the api service, got %+v", bp.Services)
+	}
+
+	fromString, err := LoadFromString(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(fromString, bp) {
+		t.Errorf("expected LoadFromString to match LoadFromBytes, got %+v", fromString)
+	}
+
+	// LoadFromFile parses the same way
+	path := filepath.Join(t.TempDir(), "render.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	fromFile, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(fromFile, bp) {
+		t.Errorf("expected LoadFromFile to match LoadFromBytes, got %+v", fromFile)
+	}
+
+	if _, err := LoadFromString("services: [unterminated"); err == nil || !strings.Contains(err.Error(), "failed to unmarshal YAML") {
+		t.Errorf("expected a wrapped unmarshal error, got %v", err)
+	}
+}
+
+func TestMultilineEnvValueRoundTrip(t *testing.T) {
+	const cert = "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIU\n-----END CERTIFICATE-----\n"
+	bp := NewBlueprint().WithServices(NewWebService("api", RuntimeNode).WithEnv("TLS_CERT", cert))
+
+	out, err := bp.ToYAMLString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "value: |") {
+		t.Errorf("expected a literal block scalar, got:\n%s", out)
+	}
+
+	loaded, err := LoadFromString(out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := *loaded.Services[0].EnvVars[0].Value; got != cert {
+		t.Errorf("expected %q after the round trip, got %q", cert, got)
+	}
+}
+
+func TestStripSecretValues(t *testing.T) {
+	secret := EnvSecret("API_KEY")
+	secret.Value = stringPtr("sk-live-123")
+	password := EnvSecret("DB_PASSWORD")
+	password.Value = stringPtr("hunter2")
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).
+			WithEnv("LOG_LEVEL", "info").
+			WithEnvVars(secret)).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithEnvVars(password))
+
+	stripped := bp.StripSecretValues()
+
+	apiKey := stripped.Services[0].EnvVars[1]
+	if apiKey.Value != nil || *apiKey.Key != "API_KEY" || apiKey.Sync == nil || *apiKey.Sync {
+		t.Errorf("expected API_KEY to keep its key and sync flag without a value, got %+v", apiKey)
+	}
+	if stripped.EnvVarGroups[0].EnvVars[0].Value != nil {
+		t.Errorf("expected the group secret value to be removed, got %q", *stripped.EnvVarGroups[0].EnvVars[0].Value)
+	}
+	if got := *stripped.Services[0].EnvVars[0].Value; got != "info" {
+		t.Errorf("expected literal values to be kept, got %q", got)
+	}
+
+	out, err := stripped.ToYAMLString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "sk-live-123") || strings.Contains(out, "hunter2") {
+		t.Errorf("expected no secret values in the output, got:\n%s", out)
+	}
+
+	// The original keeps its values
+	if got := *bp.Services[0].EnvVars[1].Value; got != "sk-live-123" {
+		t.Errorf("expected the original to be unchanged, got %q", got)
+	}
+}
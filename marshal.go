@@ -1,6 +1,11 @@
 package render
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,17 +20,55 @@ type CustomBlueprint struct {
 	RawServices []ServiceMarshalable `yaml:"-"`
 }
 
+// MarshalOptions controls how a blueprint is rendered to YAML
+// The zero value matches MarshalYAML
+type MarshalOptions struct {
+	// EmitEmptySlices writes slices that were explicitly set to an empty value as [].
+	// Nil slices are always omitted. An explicitly-empty ipAllowList is written in
+	// either mode, since it denies all external connections (see WithPrivateAccess)
+	// whereas an omitted one leaves Render's default policy in place.
+	EmitEmptySlices bool
+	// SortEnvVars orders env vars alphabetically by key within each service and group.
+	// Only runs of keyed vars between fromGroup inclusions are sorted, since that order sets precedence.
+	SortEnvVars bool
+}
+
+// DefaultMarshalOptions returns the options used by MarshalYAML
+func DefaultMarshalOptions() MarshalOptions {
+	return MarshalOptions{}
+}
+
+// alwaysEmittedEmptySlices are keys whose explicitly-empty value is written even when
+// empty slices are otherwise omitted, because empty and absent mean different things
+var alwaysEmittedEmptySlices = map[string]bool{
+	"ipAllowList": true,
+}
+
 // MarshalYAML implements custom YAML marshaling for Blueprint to handle different service types
 func (bp *Blueprint) MarshalYAML() (interface{}, error) {
+	return bp.marshalYAMLWithOptions(DefaultMarshalOptions())
+}
+
+// marshalYAMLWithOptions builds the YAML representation of the blueprint
+func (bp *Blueprint) marshalYAMLWithOptions(opts MarshalOptions) (interface{}, error) {
 	type Alias Blueprint
 	
 	// Create a map to hold the final structure
 	result := make(map[string]interface{})
 	
 	// Marshal the blueprint without services first
+	envVarGroups := bp.EnvVarGroups
+	if opts.SortEnvVars && envVarGroups != nil {
+		envVarGroups = make([]EnvVarGroup, len(bp.EnvVarGroups))
+		for i, group := range bp.EnvVarGroups {
+			group.EnvVars = sortedEnvVars(group.EnvVars)
+			envVarGroups[i] = group
+		}
+	}
+
 	temp := &Alias{
 		Databases:               bp.Databases,
-		EnvVarGroups:            bp.EnvVarGroups,
+		EnvVarGroups:            envVarGroups,
 		Previews:                bp.Previews,
 		PreviewsExpireAfterDays: bp.PreviewsExpireAfterDays,
 	}
@@ -40,70 +83,247 @@ func (bp *Blueprint) MarshalYAML() (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// Put back explicitly-empty slices that omitempty dropped
+	restoreEmptySlices(result, reflect.ValueOf(temp), opts.EmitEmptySlices)
+	if opts.EmitEmptySlices && bp.Services != nil && len(bp.Services) == 0 {
+		result["services"] = []interface{}{}
+	}
 	
 	// Handle services separately
 	if len(bp.Services) > 0 {
 		services := make([]interface{}, len(bp.Services))
 		for i, service := range bp.Services {
-			// Check if this is a static site (web + static runtime + has staticPublishPath)
-			if service.Type == ServiceTypeWeb && 
-			   service.Runtime != nil && 
-			   *service.Runtime == RuntimeStatic && 
-			   service.StaticPublishPath != nil {
-				// Marshal as staticService format
-				staticData := map[string]interface{}{
-					"name":    service.Name,
-					"type":    "web",
-					"runtime": "static",
-				}
-				
-				// Add optional fields
-				if service.BuildCommand != nil {
-					staticData["buildCommand"] = *service.BuildCommand
-				}
-				if service.StaticPublishPath != nil {
-					staticData["staticPublishPath"] = *service.StaticPublishPath
-				}
-				if service.Repo != nil {
-					staticData["repo"] = *service.Repo
-				}
-				if service.Branch != nil {
-					staticData["branch"] = *service.Branch
-				}
-				if len(service.Domains) > 0 {
-					staticData["domains"] = service.Domains
-				}
-				// Note: region is not supported for static services in the Render schema
-				if len(service.Headers) > 0 {
-					staticData["headers"] = service.Headers
-				}
-				if len(service.Routes) > 0 {
-					staticData["routes"] = service.Routes
-				}
-				if service.AutoDeploy != nil {
-					staticData["autoDeploy"] = *service.AutoDeploy
-				}
-				if service.BuildFilter != nil {
-					staticData["buildFilter"] = service.BuildFilter
-				}
-				if service.RootDir != nil {
-					staticData["rootDir"] = *service.RootDir
-				}
-				if len(service.EnvVars) > 0 {
-					staticData["envVars"] = service.EnvVars
-				}
-				if service.Previews != nil {
-					staticData["previews"] = service.Previews
-				}
-				
-				services[i] = staticData
-			} else {
-				// Marshal as regular service
-				services[i] = service
+			if opts.SortEnvVars {
+				service.EnvVars = sortedEnvVars(service.EnvVars)
+			}
+			serviceData, err := marshalServiceYAML(service, opts)
+			if err != nil {
+				return nil, err
 			}
+			services[i] = serviceData
 		}
 		result["services"] = services
 	}
 	
 	return result, nil
-}
\ No newline at end of file
+}
+
+// MarshalServices renders just a YAML sequence of services, without the services key,
+// for splicing into a hand-written render.yaml
+func MarshalServices(services []Service) ([]byte, error) {
+	values := make([]interface{}, len(services))
+	for i, service := range services {
+		value, err := marshalServiceYAML(service, DefaultMarshalOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal service %s to YAML: %w", service.Name, err)
+		}
+		values[i] = value
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal services to YAML: %w", err)
+	}
+	return data, nil
+}
+
+// marshalServiceYAML converts a single service to its YAML form
+// Static sites are reshaped to match the staticService schema
+func marshalServiceYAML(service Service, opts MarshalOptions) (interface{}, error) {
+	// Check if this is a static site (web + static runtime + has staticPublishPath)
+	if service.Type == ServiceTypeWeb && 
+	   service.Runtime != nil && 
+	   *service.Runtime == RuntimeStatic && 
+	   service.StaticPublishPath != nil {
+		// Marshal as staticService format
+		staticData := map[string]interface{}{
+			"name":    service.Name,
+			"type":    "web",
+			"runtime": "static",
+		}
+		
+		// Add optional fields
+		if service.BuildCommand != nil {
+			staticData["buildCommand"] = *service.BuildCommand
+		}
+		if service.StaticPublishPath != nil {
+			staticData["staticPublishPath"] = *service.StaticPublishPath
+		}
+		if service.Repo != nil {
+			staticData["repo"] = *service.Repo
+		}
+		if service.Branch != nil {
+			staticData["branch"] = *service.Branch
+		}
+		if len(service.Domains) > 0 {
+			staticData["domains"] = service.Domains
+		}
+		// Note: region is not supported for static services in the Render schema
+		if len(service.Headers) > 0 {
+			staticData["headers"] = service.Headers
+		}
+		if len(service.Routes) > 0 {
+			staticData["routes"] = service.Routes
+		}
+		if service.AutoDeploy != nil {
+			staticData["autoDeploy"] = *service.AutoDeploy
+		}
+		if service.BuildFilter != nil {
+			staticData["buildFilter"] = service.BuildFilter
+		}
+		if service.RootDir != nil {
+			staticData["rootDir"] = *service.RootDir
+		}
+		if len(service.EnvVars) > 0 {
+			staticData["envVars"] = service.EnvVars
+		}
+		if service.Previews != nil {
+			staticData["previews"] = service.Previews
+		}
+		
+		return staticData, nil
+	}
+
+	if opts.EmitEmptySlices {
+		// Marshal as regular service, keeping explicitly-empty slices
+		serviceData, err := toYAMLMap(service)
+		if err != nil {
+			return nil, err
+		}
+		restoreEmptySlices(serviceData, reflect.ValueOf(service), true)
+		return serviceData, nil
+	}
+
+	if service.IPAllowList != nil && len(service.IPAllowList) == 0 {
+		// Keep the field order of the struct and append the deny-all allow list
+		var node yaml.Node
+		if err := node.Encode(service); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "ipAllowList"},
+			&yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle},
+		)
+		return &node, nil
+	}
+
+	// Marshal as regular service
+	return service, nil
+}
+
+// sortedEnvVars returns a copy of envVars with each run of keyed entries sorted by key
+// Keyless entries such as fromGroup inclusions stay in place and bound the runs, so
+// no var moves across a group inclusion and precedence is unchanged
+func sortedEnvVars(envVars []EnvVar) []EnvVar {
+	if envVars == nil {
+		return nil
+	}
+
+	sorted := make([]EnvVar, len(envVars))
+	copy(sorted, envVars)
+
+	start := 0
+	for i := 0; i <= len(sorted); i++ {
+		if i < len(sorted) && sorted[i].Key != nil {
+			continue
+		}
+		run := sorted[start:i]
+		sort.SliceStable(run, func(a, b int) bool {
+			return *run[a].Key < *run[b].Key
+		})
+		start = i + 1
+	}
+	return sorted
+}
+
+// toYAMLMap converts a value to a generic YAML map using its struct tags
+func toYAMLMap(v interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// restoreEmptySlices adds explicitly-empty slices from v back into m, which omitempty dropped
+// Unless all is set, only the keys in alwaysEmittedEmptySlices are restored
+func restoreEmptySlices(m map[string]interface{}, v reflect.Value, all bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, inline := yamlFieldKey(field)
+		if key == "-" {
+			continue
+		}
+
+		value := v.Field(i)
+		if inline {
+			restoreEmptySlices(m, value, all)
+			continue
+		}
+
+		switch value.Kind() {
+		case reflect.Slice:
+			if value.IsNil() {
+				continue
+			}
+			if value.Len() == 0 {
+				if all || alwaysEmittedEmptySlices[key] {
+					m[key] = []interface{}{}
+				}
+				continue
+			}
+			items, ok := m[key].([]interface{})
+			if !ok || len(items) != value.Len() {
+				continue
+			}
+			for j := range items {
+				if child, ok := items[j].(map[string]interface{}); ok {
+					restoreEmptySlices(child, value.Index(j), all)
+				}
+			}
+		case reflect.Ptr, reflect.Struct:
+			if child, ok := m[key].(map[string]interface{}); ok {
+				restoreEmptySlices(child, value, all)
+			}
+		}
+	}
+}
+
+// yamlFieldKey returns the YAML key for a struct field and whether it is inlined
+func yamlFieldKey(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	parts := strings.Split(tag, ",")
+
+	inline := false
+	for _, flag := range parts[1:] {
+		if flag == "inline" {
+			inline = true
+		}
+	}
+
+	key := parts[0]
+	if key == "" {
+		key = strings.ToLower(field.Name)
+	}
+	return key, inline
+}
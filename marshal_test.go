@@ -0,0 +1,208 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalEmptySlices(t *testing.T) {
+	newBlueprint := func() *Blueprint {
+		return &Blueprint{
+			Databases: []Database{*NewDatabase("main-db").WithPrivateAccess()},
+			Services: []Service{
+				{Name: "cache", Type: ServiceTypeKeyValue, IPAllowList: []IPAllow{}},
+				{Name: "api", Type: ServiceTypeWeb, Runtime: runtimePtr(RuntimeNode), Domains: []string{}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		opts        MarshalOptions
+		expectEmpty bool
+	}{
+		{
+			name:        "default omits explicitly-empty slices",
+			opts:        DefaultMarshalOptions(),
+			expectEmpty: false,
+		},
+		{
+			name:        "explicitly-empty slices emitted on request",
+			opts:        MarshalOptions{EmitEmptySlices: true},
+			expectEmpty: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yamlStr, err := newBlueprint().ToYAMLStringWithOptions(tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Deny-all allow lists are always written
+			if count := strings.Count(yamlStr, "ipAllowList: []"); count != 2 {
+				t.Errorf("expected database and key-value ipAllowList: [] in output, got:\n%s", yamlStr)
+			}
+			if got := strings.Contains(yamlStr, "domains: []"); got != tt.expectEmpty {
+				t.Errorf("expected domains: [] present=%v, got:\n%s", tt.expectEmpty, yamlStr)
+			}
+
+			// Nil slices are omitted in both modes
+			if strings.Contains(yamlStr, "readReplicas") || strings.Contains(yamlStr, "envVars") {
+				t.Errorf("nil slices should never be emitted, got:\n%s", yamlStr)
+			}
+		})
+	}
+
+	// The zero value is the default and matches MarshalYAML
+	bp := newBlueprint()
+	zeroStr, err := bp.ToYAMLStringWithOptions(MarshalOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plainStr, err := bp.ToYAMLString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zeroStr != plainStr {
+		t.Errorf("zero options should match ToYAMLString\nExpected:\n%s\nGot:\n%s", plainStr, zeroStr)
+	}
+}
+
+func TestPrivateAccessRoundTrip(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewBackgroundWorker("worker", RuntimeNode)).
+		WithDatabases(NewDatabase("main-db").WithPrivateAccess())
+
+	yamlStr, err := bp.ToYAMLString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := LoadFromString(yamlStr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	allowList := loaded.Databases[0].IPAllowList
+	if allowList == nil || len(allowList) != 0 {
+		t.Errorf("expected a deny-all ipAllowList to survive the round trip, got %#v from:\n%s", allowList, yamlStr)
+	}
+}
+
+func TestMarshalSortEnvVars(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).
+			WithEnv("ZED", "1").
+			WithEnvVars(EnvFromGroup("shared")).
+			WithEnv("ALPHA", "2").
+			WithEnvVars(EnvFromGroup("overrides")).
+			WithEnv("MID", "3"),
+			NewBackgroundWorker("worker", RuntimeNode).
+				WithEnv("DELTA", "1").
+				WithEnv("BRAVO", "2").
+				WithEnvVars(EnvFromGroup("shared")).
+				WithEnv("ECHO", "3").
+				WithEnv("CHARLIE", "4").
+				WithEnv("ALPHA", "5")).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("B", "1").WithEnv("A", "2"))
+
+	tests := []struct {
+		name          string
+		opts          MarshalOptions
+		serviceOrder  []string
+		workerOrder   []string
+		groupKeyOrder []string
+	}{
+		{
+			name:          "unsorted keeps insertion order",
+			opts:          DefaultMarshalOptions(),
+			serviceOrder:  []string{"ZED", "group:shared", "ALPHA", "group:overrides", "MID"},
+			workerOrder:   []string{"DELTA", "BRAVO", "group:shared", "ECHO", "CHARLIE", "ALPHA"},
+			groupKeyOrder: []string{"B", "A"},
+		},
+		{
+			name:          "sorted orders keys within runs between group inclusions",
+			opts:          MarshalOptions{SortEnvVars: true},
+			serviceOrder:  []string{"ZED", "group:shared", "ALPHA", "group:overrides", "MID"},
+			workerOrder:   []string{"BRAVO", "DELTA", "group:shared", "ALPHA", "CHARLIE", "ECHO"},
+			groupKeyOrder: []string{"A", "B"},
+		},
+	}
+
+	envVarOrder := func(envVars []EnvVar) []string {
+		var order []string
+		for _, envVar := range envVars {
+			if envVar.Key != nil {
+				order = append(order, *envVar.Key)
+			} else if envVar.FromGroup != nil {
+				order = append(order, "group:"+*envVar.FromGroup)
+			}
+		}
+		return order
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yamlStr, err := bp.ToYAMLStringWithOptions(tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var parsed Blueprint
+			if err := yaml.Unmarshal([]byte(yamlStr), &parsed); err != nil {
+				t.Fatalf("failed to parse output: %v\n%s", err, yamlStr)
+			}
+
+			if got := envVarOrder(parsed.Services[0].EnvVars); !reflect.DeepEqual(got, tt.serviceOrder) {
+				t.Errorf("expected service env var order %v, got %v", tt.serviceOrder, got)
+			}
+			if got := envVarOrder(parsed.Services[1].EnvVars); !reflect.DeepEqual(got, tt.workerOrder) {
+				t.Errorf("expected worker env var order %v, got %v", tt.workerOrder, got)
+			}
+			if got := envVarOrder(parsed.EnvVarGroups[0].EnvVars); !reflect.DeepEqual(got, tt.groupKeyOrder) {
+				t.Errorf("expected group env var order %v, got %v", tt.groupKeyOrder, got)
+			}
+		})
+	}
+
+	// Sorting never reorders the blueprint itself
+	if got := envVarOrder(bp.Services[0].EnvVars); got[0] != "ZED" {
+		t.Errorf("expected the blueprint to keep its order, got %v", got)
+	}
+}
+
+func TestMarshalServices(t *testing.T) {
+	bp := NewBlueprint().WithServices(
+		NewWebService("api", RuntimeNode).WithPlan(PlanStandard).WithEnv("LOG_LEVEL", "info"),
+		NewStaticSite("frontend").WithPublishPath("./dist").WithBuild("npm run build"),
+	)
+
+	fragment, err := MarshalServices(bp.Services)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	full, err := bp.ToYAMLBytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(full, &document); err != nil {
+		t.Fatalf("failed to parse full blueprint: %v", err)
+	}
+	var services []interface{}
+	if err := yaml.Unmarshal(fragment, &services); err != nil {
+		t.Fatalf("failed to parse fragment: %v\n%s", err, fragment)
+	}
+
+	if !reflect.DeepEqual(services, document["services"]) {
+		t.Errorf("expected the services portion of the full blueprint\nExpected:\n%v\nGot:\n%v", document["services"], services)
+	}
+	if strings.Contains(string(fragment), "services:") {
+		t.Errorf("expected no services key in the fragment:\n%s", fragment)
+	}
+}
@@ -0,0 +1,192 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadNode loads a YAML file as a node tree, keeping comments and key order
+// Edit the tree with ServiceNode, SetNodeValue and UpdateServiceNode, then save it with WriteNode
+func LoadNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML from %s: %w", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s does not contain a blueprint mapping", path)
+	}
+
+	return &doc, nil
+}
+
+// ServiceNode returns the mapping node of the named service, or nil if there is none
+func ServiceNode(doc *yaml.Node, name string) *yaml.Node {
+	services := mappingValue(nodeRoot(doc), "services")
+	if services == nil || services.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for _, service := range services.Content {
+		if nameNode := mappingValue(service, "name"); nameNode != nil && nameNode.Value == name {
+			return service
+		}
+	}
+	return nil
+}
+
+// SetNodeValue sets key in a mapping node to value
+// An existing key keeps its position and comments; a new key is appended
+func SetNodeValue(mapping *yaml.Node, key string, value interface{}) error {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot set %s on a non-mapping node", key)
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	if index := mappingIndex(mapping, key); index >= 0 {
+		valueNode.LineComment = mapping.Content[index].LineComment
+		mapping.Content[index] = &valueNode
+		return nil
+	}
+
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	mapping.Content = append(mapping.Content, keyNode, &valueNode)
+	return nil
+}
+
+// UpdateServiceNode rewrites the named service in the node tree to match service
+// Only keys whose values changed are replaced, so comments on untouched keys
+// survive; keys the Service type does not model are kept, and a service
+// missing from the tree is appended
+func UpdateServiceNode(doc *yaml.Node, service Service) error {
+	value, err := marshalServiceYAML(service, DefaultMarshalOptions())
+	if err != nil {
+		return fmt.Errorf("failed to marshal service %s: %w", service.Name, err)
+	}
+	var updated yaml.Node
+	if err := updated.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode service %s: %w", service.Name, err)
+	}
+
+	existing := ServiceNode(doc, service.Name)
+	if existing == nil {
+		root := nodeRoot(doc)
+		if root == nil {
+			return fmt.Errorf("node tree does not contain a blueprint mapping")
+		}
+		services := mappingValue(root, "services")
+		if services == nil {
+			if err := SetNodeValue(root, "services", []interface{}{}); err != nil {
+				return err
+			}
+			services = mappingValue(root, "services")
+		}
+		services.Content = append(services.Content, &updated)
+		return nil
+	}
+
+	// Drop keys the service no longer sets, leaving keys this library does not model alone
+	modeled := modeledYAMLKeys(reflect.TypeOf(Service{}))
+	for i := 0; i+1 < len(existing.Content); {
+		key := existing.Content[i].Value
+		if modeled[key] && mappingIndex(&updated, key) < 0 {
+			existing.Content = append(existing.Content[:i], existing.Content[i+2:]...)
+			continue
+		}
+		i += 2
+	}
+
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		key, valueNode := updated.Content[i].Value, updated.Content[i+1]
+		if current := mappingValue(existing, key); current != nil && nodesEqual(current, valueNode) {
+			continue
+		}
+		if err := SetNodeValue(existing, key, valueNode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteNode writes a node tree to a YAML file with two-space indentation
+func WriteNode(doc *yaml.Node, path string) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal node to YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to marshal node to YAML: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+	return nil
+}
+
+// nodeRoot returns the top-level mapping of a document node, or nil
+func nodeRoot(doc *yaml.Node) *yaml.Node {
+	if doc == nil {
+		return nil
+	}
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return nil
+		}
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// modeledYAMLKeys returns the YAML keys of a struct type, including those of inlined fields
+func modeledYAMLKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, inline := yamlFieldKey(field)
+		if key == "-" {
+			continue
+		}
+		if inline {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				for inlineKey := range modeledYAMLKeys(fieldType) {
+					keys[inlineKey] = true
+				}
+			}
+			continue
+		}
+		keys[key] = true
+	}
+	return keys
+}
+
+// nodesEqual reports whether two nodes decode to the same value, ignoring comments and style
+func nodesEqual(a, b *yaml.Node) bool {
+	var left, right interface{}
+	if a.Decode(&left) != nil || b.Decode(&right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
@@ -0,0 +1,163 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNodeRoundTrip(t *testing.T) {
+	const original = `# Production blueprint, edit with care
+services:
+  # Public API
+  - name: api
+    type: web
+    runtime: node
+    plan: starter # bump before launch
+    startCommand: npm start
+  # Queue consumer
+  - name: worker
+    type: worker
+    runtime: node
+    plan: starter
+`
+
+	load := func(t *testing.T) (string, func() string) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "render.yaml")
+		if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+		read := func() string {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			return string(data)
+		}
+		return path, read
+	}
+
+	assertComments := func(t *testing.T, out string) {
+		t.Helper()
+		for _, comment := range []string{"# Production blueprint, edit with care", "# Public API", "# Queue consumer"} {
+			if !strings.Contains(out, comment) {
+				t.Errorf("expected comment %q to survive, got:\n%s", comment, out)
+			}
+		}
+	}
+
+	t.Run("set a single value", func(t *testing.T) {
+		path, read := load(t)
+		doc, err := LoadNode(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := SetNodeValue(ServiceNode(doc, "api"), "plan", PlanStandard); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := WriteNode(doc, path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out := read()
+		assertComments(t, out)
+		if !strings.Contains(out, "plan: standard # bump before launch") {
+			t.Errorf("expected the plan to change in place, got:\n%s", out)
+		}
+
+		bp, err := LoadFromFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *bp.Services[0].Plan != PlanStandard || *bp.Services[1].Plan != PlanStarter {
+			t.Errorf("expected only the api plan to change, got %s and %s", *bp.Services[0].Plan, *bp.Services[1].Plan)
+		}
+	})
+
+	t.Run("apply a service edit", func(t *testing.T) {
+		path, read := load(t)
+		doc, err := LoadNode(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		bp, err := LoadFromFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		worker := bp.Services[1]
+		worker.Plan = planPtr(PlanStandard)
+		if err := UpdateServiceNode(doc, worker); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := WriteNode(doc, path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out := read()
+		assertComments(t, out)
+		if !strings.Contains(out, "plan: starter # bump before launch") {
+			t.Errorf("expected the untouched api service to keep its line comment, got:\n%s", out)
+		}
+		if strings.Index(out, "name: api") > strings.Index(out, "name: worker") {
+			t.Errorf("expected service order to be preserved, got:\n%s", out)
+		}
+
+		reloaded, err := LoadFromFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *reloaded.Services[1].Plan != PlanStandard {
+			t.Errorf("expected the worker plan to change, got %s", *reloaded.Services[1].Plan)
+		}
+	})
+
+	t.Run("keep unmodeled keys and an empty allow list", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "render.yaml")
+		const fixture = `services:
+  - name: cache
+    type: keyvalue
+    plan: starter
+    x-team: core # owned by platform
+    ipAllowList: []
+`
+		if err := os.WriteFile(path, []byte(fixture), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+
+		doc, err := LoadNode(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bp, err := LoadFromFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cache := bp.Services[0]
+		cache.Plan = planPtr(PlanStandard)
+		if err := UpdateServiceNode(doc, cache); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := WriteNode(doc, path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		out := string(data)
+		if !strings.Contains(out, "x-team: core # owned by platform") {
+			t.Errorf("expected the unmodeled key to survive, got:\n%s", out)
+		}
+		if !strings.Contains(out, "ipAllowList: []") {
+			t.Errorf("expected the empty allow list to survive, got:\n%s", out)
+		}
+		if !strings.Contains(out, "plan: standard") {
+			t.Errorf("expected the plan to change, got:\n%s", out)
+		}
+	})
+}
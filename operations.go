@@ -1,26 +1,61 @@
 package render
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 )
 
+// MergeStrategy controls how MergeBlueprintsWithStrategy handles overlapping resources
+// Strategies are bit flags and can be combined
+type MergeStrategy int
+
+const (
+	// MergeStrategyStrict fails on any name conflict
+	MergeStrategyStrict MergeStrategy = 0
+	// MergeStrategyDedupIdentical collapses same-named resources that are value-equal;
+	// same-named resources that differ still fail
+	MergeStrategyDedupIdentical MergeStrategy = 1 << 0
+	// MergeStrategySortByName sorts the merged services, databases and groups by name
+	// so the output order does not depend on the order layers were merged in
+	MergeStrategySortByName MergeStrategy = 1 << 1
+)
+
 // MergeBlueprints combines two blueprints into one
 // Returns an error if there are any name conflicts
 // Use PrefixBlueprint() to avoid conflicts before merging
 func MergeBlueprints(base, overlay *Blueprint) (*Blueprint, error) {
+	return MergeBlueprintsWithStrategy(base, overlay, MergeStrategyStrict)
+}
+
+// MergeBlueprintsWithStrategy combines two blueprints into one using the given strategy
+func MergeBlueprintsWithStrategy(base, overlay *Blueprint, strategy MergeStrategy) (*Blueprint, error) {
 	if base == nil && overlay == nil {
 		return &Blueprint{}, nil
 	}
 	if base == nil {
-		return CopyBlueprint(overlay), nil
+		return applyMergeOrder(CopyBlueprint(overlay), strategy), nil
 	}
 	if overlay == nil {
-		return CopyBlueprint(base), nil
+		return applyMergeOrder(CopyBlueprint(base), strategy), nil
 	}
 
 	// Check for conflicts first
-	conflicts := FindConflicts(base, overlay)
+	var conflicts []string
+	duplicates := make(map[ResourceKind]map[string]bool)
+	for _, conflict := range FindConflictsDetailed(base, overlay) {
+		if conflict.Identical && strategy&MergeStrategyDedupIdentical != 0 {
+			if duplicates[conflict.Kind] == nil {
+				duplicates[conflict.Kind] = make(map[string]bool)
+			}
+			duplicates[conflict.Kind][conflict.Name] = true
+			continue
+		}
+		conflicts = append(conflicts, conflict.String())
+	}
 	if len(conflicts) > 0 {
 		return nil, fmt.Errorf("merge conflicts found: %s. Use PrefixBlueprint() to avoid name conflicts before merging", strings.Join(conflicts, ", "))
 	}
@@ -29,15 +64,27 @@ func MergeBlueprints(base, overlay *Blueprint) (*Blueprint, error) {
 
 	// Combine services
 	merged.Services = append(merged.Services, base.Services...)
-	merged.Services = append(merged.Services, overlay.Services...)
+	for _, service := range overlay.Services {
+		if !duplicates[ResourceKindService][service.Name] {
+			merged.Services = append(merged.Services, service)
+		}
+	}
 
 	// Combine databases
 	merged.Databases = append(merged.Databases, base.Databases...)
-	merged.Databases = append(merged.Databases, overlay.Databases...)
+	for _, db := range overlay.Databases {
+		if !duplicates[ResourceKindDatabase][db.Name] {
+			merged.Databases = append(merged.Databases, db)
+		}
+	}
 
 	// Combine environment variable groups
 	merged.EnvVarGroups = append(merged.EnvVarGroups, base.EnvVarGroups...)
-	merged.EnvVarGroups = append(merged.EnvVarGroups, overlay.EnvVarGroups...)
+	for _, group := range overlay.EnvVarGroups {
+		if !duplicates[ResourceKindEnvVarGroup][group.Name] {
+			merged.EnvVarGroups = append(merged.EnvVarGroups, group)
+		}
+	}
 
 	// Overlay wins for preview configuration
 	if overlay.Previews != nil {
@@ -53,7 +100,64 @@ func MergeBlueprints(base, overlay *Blueprint) (*Blueprint, error) {
 		merged.PreviewsExpireAfterDays = base.PreviewsExpireAfterDays
 	}
 
-	return merged, nil
+	return applyMergeOrder(merged, strategy), nil
+}
+
+// applyMergeOrder sorts the merged resources by name when the strategy asks for it
+func applyMergeOrder(merged *Blueprint, strategy MergeStrategy) *Blueprint {
+	if strategy&MergeStrategySortByName == 0 {
+		return merged
+	}
+
+	sort.SliceStable(merged.Services, func(i, j int) bool {
+		return merged.Services[i].Name < merged.Services[j].Name
+	})
+	sort.SliceStable(merged.Databases, func(i, j int) bool {
+		return merged.Databases[i].Name < merged.Databases[j].Name
+	})
+	sort.SliceStable(merged.EnvVarGroups, func(i, j int) bool {
+		return merged.EnvVarGroups[i].Name < merged.EnvVarGroups[j].Name
+	})
+	return merged
+}
+
+// MergeBlueprintsTracked merges named layers and records where each resource came from
+// Layers are merged in lexical order of their names so the result is deterministic
+// The returned provenance map goes from resource name to the name of the layer that defined it
+func MergeBlueprintsTracked(layers map[string]*Blueprint) (*Blueprint, map[string]string, error) {
+	layerNames := make([]string, 0, len(layers))
+	for name := range layers {
+		layerNames = append(layerNames, name)
+	}
+	sort.Strings(layerNames)
+
+	merged := &Blueprint{}
+	provenance := make(map[string]string)
+
+	for _, layerName := range layerNames {
+		layer := layers[layerName]
+		if layer == nil {
+			continue
+		}
+
+		result, err := MergeBlueprints(merged, layer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to merge layer %s: %w", layerName, err)
+		}
+		merged = result
+
+		for _, service := range layer.Services {
+			provenance[service.Name] = layerName
+		}
+		for _, db := range layer.Databases {
+			provenance[db.Name] = layerName
+		}
+		for _, group := range layer.EnvVarGroups {
+			provenance[group.Name] = layerName
+		}
+	}
+
+	return merged, provenance, nil
 }
 
 // CopyBlueprint creates a deep copy of a blueprint
@@ -66,15 +170,21 @@ func CopyBlueprint(bp *Blueprint) *Blueprint {
 
 	// Copy services
 	copied.Services = make([]Service, len(bp.Services))
-	copy(copied.Services, bp.Services)
+	for i, service := range bp.Services {
+		copied.Services[i] = cloneOf(service)
+	}
 
 	// Copy databases
 	copied.Databases = make([]Database, len(bp.Databases))
-	copy(copied.Databases, bp.Databases)
+	for i, db := range bp.Databases {
+		copied.Databases[i] = cloneOf(db)
+	}
 
 	// Copy environment variable groups
 	copied.EnvVarGroups = make([]EnvVarGroup, len(bp.EnvVarGroups))
-	copy(copied.EnvVarGroups, bp.EnvVarGroups)
+	for i, group := range bp.EnvVarGroups {
+		copied.EnvVarGroups[i] = cloneOf(group)
+	}
 
 	// Copy preview configuration
 	if bp.Previews != nil {
@@ -91,117 +201,260 @@ func CopyBlueprint(bp *Blueprint) *Blueprint {
 	return copied
 }
 
-// ValidateBlueprint checks for common issues in a blueprint
-func ValidateBlueprint(bp *Blueprint) []string {
-	var errors []string
-
-	if bp == nil {
-		return []string{"blueprint is nil"}
-	}
+// cloneOf returns a copy of v that shares no pointers, slices or maps with it
+func cloneOf[T any](v T) T {
+	return deepCopy(reflect.ValueOf(v)).Interface().(T)
+}
 
-	// Check for duplicate service names
-	serviceNames := make(map[string]bool)
-	for _, service := range bp.Services {
-		if serviceNames[service.Name] {
-			errors = append(errors, fmt.Sprintf("duplicate service name: %s", service.Name))
+// deepCopy recursively copies the exported content of a value
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
 		}
-		serviceNames[service.Name] = true
-	}
-
-	// Check for duplicate database names
-	dbNames := make(map[string]bool)
-	for _, db := range bp.Databases {
-		if dbNames[db.Name] {
-			errors = append(errors, fmt.Sprintf("duplicate database name: %s", db.Name))
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(deepCopy(v.Elem()))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopy(v.Index(i)))
 		}
-		dbNames[db.Name] = true
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			copied.SetMapIndex(key, deepCopy(v.MapIndex(key)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				copied.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return copied
+	default:
+		return v
 	}
+}
 
-	// Check for duplicate environment group names
-	envGroupNames := make(map[string]bool)
-	for _, group := range bp.EnvVarGroups {
-		if envGroupNames[group.Name] {
-			errors = append(errors, fmt.Sprintf("duplicate environment group name: %s", group.Name))
-		}
-		envGroupNames[group.Name] = true
+// Equal reports whether two blueprints have the same content
+// Nil and empty slices are treated as equal, so a blueprint equals its YAML round trip
+func (bp *Blueprint) Equal(other *Blueprint) bool {
+	if bp == nil || other == nil {
+		return bp == other
 	}
+	return valuesEqual(reflect.ValueOf(*bp), reflect.ValueOf(*other))
+}
 
-	// Check for missing required fields
-	for _, service := range bp.Services {
-		if service.Name == "" {
-			errors = append(errors, "service missing name")
+// valuesEqual compares exported content of two values of the same type
+func valuesEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
 		}
-		if service.Type == "" {
-			errors = append(errors, fmt.Sprintf("service %s missing type", service.Name))
+		return valuesEqual(a.Elem(), b.Elem())
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
 		}
-		// Runtime required for most service types
-		if service.Runtime == nil && service.Type != ServiceTypeKeyValue {
-			errors = append(errors, fmt.Sprintf("service %s missing runtime", service.Name))
+		for i := 0; i < a.Len(); i++ {
+			if !valuesEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
 		}
+		for _, key := range a.MapKeys() {
+			bValue := b.MapIndex(key)
+			if !bValue.IsValid() || !valuesEqual(a.MapIndex(key), bValue) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !a.Type().Field(i).IsExported() {
+				continue
+			}
+			if !valuesEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Interface() == b.Interface()
 	}
+}
 
-	for _, db := range bp.Databases {
-		if db.Name == "" {
-			errors = append(errors, "database missing name")
+// Hash returns a stable SHA-256 hex digest of the blueprint's content
+// Resource ordering does not affect the hash, so it can be used for change detection
+func (bp *Blueprint) Hash() (string, error) {
+	if bp == nil {
+		return "", fmt.Errorf("blueprint is nil")
+	}
+
+	// Normalize resource order before serializing
+	normalized := CopyBlueprint(bp)
+	sort.SliceStable(normalized.Services, func(i, j int) bool {
+		return normalized.Services[i].Name < normalized.Services[j].Name
+	})
+	sort.SliceStable(normalized.Databases, func(i, j int) bool {
+		return normalized.Databases[i].Name < normalized.Databases[j].Name
+	})
+	sort.SliceStable(normalized.EnvVarGroups, func(i, j int) bool {
+		return normalized.EnvVarGroups[i].Name < normalized.EnvVarGroups[j].Name
+	})
+
+	data, err := normalized.ToYAMLBytes()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ValidateBlueprint checks for common issues in a blueprint
+// Only error-severity issues are returned; use ValidateBlueprintDetailed for warnings
+func ValidateBlueprint(bp *Blueprint) []string {
+	var errors []string
+
+	for _, issue := range ValidateBlueprintDetailed(bp) {
+		if issue.Severity == SeverityError {
+			errors = append(errors, issue.Message)
 		}
 	}
 
-	for _, group := range bp.EnvVarGroups {
-		if group.Name == "" {
-			errors = append(errors, "environment group missing name")
+	return errors
+}
+
+// ValidateBlueprintStrict is ValidateBlueprint plus a check that every env var
+// reference resolves to a resource in the blueprint
+// Use it for self-contained blueprints; external references are reported as errors
+func ValidateBlueprintStrict(bp *Blueprint) []string {
+	var errors []string
+
+	for _, issue := range ValidateBlueprintWithOptions(bp, ValidationOptions{StrictReferences: true}) {
+		if issue.Severity == SeverityError {
+			errors = append(errors, issue.Message)
 		}
 	}
 
 	return errors
 }
 
+// ResourceKind identifies a kind of named blueprint resource
+type ResourceKind string
+
+const (
+	ResourceKindService     ResourceKind = "service"
+	ResourceKindDatabase    ResourceKind = "database"
+	ResourceKindEnvVarGroup ResourceKind = "environment group"
+)
+
+// Conflict describes a resource name defined in both blueprints being merged
+type Conflict struct {
+	Kind ResourceKind
+	Name string
+	// Identical reports whether both definitions are value-equal
+	Identical bool
+}
+
+// String returns the conflict in the form used by FindConflicts
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s name conflict: %s", c.Kind, c.Name)
+}
+
 // FindConflicts identifies name conflicts between two blueprints
 func FindConflicts(base, overlay *Blueprint) []string {
 	var conflicts []string
 
+	for _, conflict := range FindConflictsDetailed(base, overlay) {
+		conflicts = append(conflicts, conflict.String())
+	}
+
+	return conflicts
+}
+
+// FindConflictsDetailed identifies name conflicts between two blueprints,
+// noting whether the conflicting definitions are identical
+func FindConflictsDetailed(base, overlay *Blueprint) []Conflict {
+	var conflicts []Conflict
+
 	if base == nil || overlay == nil {
 		return conflicts
 	}
 
 	// Check service name conflicts
-	baseServiceNames := make(map[string]bool)
-	for _, service := range base.Services {
-		baseServiceNames[service.Name] = true
-	}
 	for _, service := range overlay.Services {
-		if baseServiceNames[service.Name] {
-			conflicts = append(conflicts, fmt.Sprintf("service name conflict: %s", service.Name))
+		if existing := base.FindService(service.Name); existing != nil {
+			conflicts = append(conflicts, Conflict{
+				Kind:      ResourceKindService,
+				Name:      service.Name,
+				Identical: valuesEqual(reflect.ValueOf(*existing), reflect.ValueOf(service)),
+			})
 		}
 	}
 
 	// Check database name conflicts
-	baseDBNames := make(map[string]bool)
-	for _, db := range base.Databases {
-		baseDBNames[db.Name] = true
-	}
 	for _, db := range overlay.Databases {
-		if baseDBNames[db.Name] {
-			conflicts = append(conflicts, fmt.Sprintf("database name conflict: %s", db.Name))
+		if existing := base.FindDatabase(db.Name); existing != nil {
+			conflicts = append(conflicts, Conflict{
+				Kind:      ResourceKindDatabase,
+				Name:      db.Name,
+				Identical: valuesEqual(reflect.ValueOf(*existing), reflect.ValueOf(db)),
+			})
 		}
 	}
 
 	// Check environment group name conflicts
-	baseEnvGroupNames := make(map[string]bool)
-	for _, group := range base.EnvVarGroups {
-		baseEnvGroupNames[group.Name] = true
-	}
 	for _, group := range overlay.EnvVarGroups {
-		if baseEnvGroupNames[group.Name] {
-			conflicts = append(conflicts, fmt.Sprintf("environment group name conflict: %s", group.Name))
+		if existing := base.FindEnvVarGroup(group.Name); existing != nil {
+			conflicts = append(conflicts, Conflict{
+				Kind:      ResourceKindEnvVarGroup,
+				Name:      group.Name,
+				Identical: valuesEqual(reflect.ValueOf(*existing), reflect.ValueOf(group)),
+			})
 		}
 	}
 
 	return conflicts
 }
 
+// PrefixOptions controls how PrefixBlueprintWithOptions builds prefixed names
+type PrefixOptions struct {
+	// Separator is placed between the prefix and the original name
+	Separator string
+	// Lowercase lowercases the combined name
+	Lowercase bool
+	// PrefixDiskNames also prefixes the names of service disks
+	PrefixDiskNames bool
+}
+
 // PrefixBlueprint adds a prefix to all named resources and updates internal references
 // External references (to resources not defined in this blueprint) are left unchanged
 func PrefixBlueprint(bp *Blueprint, prefix string) *Blueprint {
+	return PrefixBlueprintWithOptions(bp, prefix, PrefixOptions{})
+}
+
+// PrefixBlueprintWithOptions adds a prefix to all named resources using the given options
+// and updates internal references
+func PrefixBlueprintWithOptions(bp *Blueprint, prefix string, opts PrefixOptions) *Blueprint {
 	if bp == nil || prefix == "" {
 		return CopyBlueprint(bp)
 	}
@@ -209,6 +462,14 @@ func PrefixBlueprint(bp *Blueprint, prefix string) *Blueprint {
 	// Create a deep copy to avoid modifying the original
 	prefixed := CopyBlueprint(bp)
 
+	rename := func(name string) string {
+		combined := prefix + opts.Separator + name
+		if opts.Lowercase {
+			combined = strings.ToLower(combined)
+		}
+		return combined
+	}
+
 	// Collect all names that exist in this blueprint
 	existingServiceNames := make(map[string]bool)
 	existingDatabaseNames := make(map[string]bool)
@@ -230,13 +491,13 @@ func PrefixBlueprint(bp *Blueprint, prefix string) *Blueprint {
 	envGroupNameMap := make(map[string]string)
 
 	for oldName := range existingServiceNames {
-		serviceNameMap[oldName] = prefix + oldName
+		serviceNameMap[oldName] = rename(oldName)
 	}
 	for oldName := range existingDatabaseNames {
-		databaseNameMap[oldName] = prefix + oldName
+		databaseNameMap[oldName] = rename(oldName)
 	}
 	for oldName := range existingEnvGroupNames {
-		envGroupNameMap[oldName] = prefix + oldName
+		envGroupNameMap[oldName] = rename(oldName)
 	}
 
 	// Update service names
@@ -246,10 +507,27 @@ func PrefixBlueprint(bp *Blueprint, prefix string) *Blueprint {
 		}
 	}
 
-	// Update database names
+	// Update database names and read replica names that reference the parent database
 	for i := range prefixed.Databases {
-		if newName, exists := databaseNameMap[prefixed.Databases[i].Name]; exists {
-			prefixed.Databases[i].Name = newName
+		db := &prefixed.Databases[i]
+		oldDBName := db.Name
+		if newName, exists := databaseNameMap[oldDBName]; exists {
+			db.Name = newName
+		}
+		for j := range db.ReadReplicas {
+			replica := &db.ReadReplicas[j]
+			if replica.Name == oldDBName || strings.HasPrefix(replica.Name, oldDBName+"-") {
+				replica.Name = rename(replica.Name)
+			}
+		}
+	}
+
+	// Update disk names
+	if opts.PrefixDiskNames {
+		for i := range prefixed.Services {
+			if disk := prefixed.Services[i].Disk; disk != nil {
+				disk.Name = rename(disk.Name)
+			}
 		}
 	}
 
@@ -298,31 +576,162 @@ func PrefixBlueprint(bp *Blueprint, prefix string) *Blueprint {
 		updateEnvVarReferences(prefixed.EnvVarGroups[i].EnvVars)
 	}
 
-	// Update read replica names that reference the parent database
-	for i := range prefixed.Databases {
-		db := &prefixed.Databases[i]
+	return prefixed
+}
+
+// PrefixBlueprintWithSeparator adds a prefix with a separator to all named resources
+func PrefixBlueprintWithSeparator(bp *Blueprint, prefix, separator string) *Blueprint {
+	if separator == "" {
+		separator = "-"
+	}
+	return PrefixBlueprintWithOptions(bp, prefix, PrefixOptions{Separator: separator})
+}
+
+// RenameService renames a service and updates every fromService reference to it
+func RenameService(bp *Blueprint, oldName, newName string) error {
+	return renameResource(bp, ResourceKindService, oldName, newName)
+}
+
+// RenameDatabase renames a database and updates every fromDatabase reference to it
+// Read replicas named after the database, such as main-db-replica, are renamed too
+func RenameDatabase(bp *Blueprint, oldName, newName string) error {
+	return renameResource(bp, ResourceKindDatabase, oldName, newName)
+}
+
+// RenameEnvVarGroup renames an environment group and updates every fromGroup reference to it
+func RenameEnvVarGroup(bp *Blueprint, oldName, newName string) error {
+	return renameResource(bp, ResourceKindEnvVarGroup, oldName, newName)
+}
+
+// renameResource renames a resource of the given kind in place and rewrites the
+// env var references to it in every service and group
+func renameResource(bp *Blueprint, kind ResourceKind, oldName, newName string) error {
+	if bp == nil {
+		return fmt.Errorf("blueprint is nil")
+	}
+
+	var names []string
+	switch kind {
+	case ResourceKindService:
+		for _, service := range bp.Services {
+			names = append(names, service.Name)
+		}
+	case ResourceKindDatabase:
+		for _, db := range bp.Databases {
+			names = append(names, db.Name)
+		}
+	case ResourceKindEnvVarGroup:
+		for _, group := range bp.EnvVarGroups {
+			names = append(names, group.Name)
+		}
+	}
+
+	index := -1
+	for i, name := range names {
+		if name == newName && oldName != newName {
+			return fmt.Errorf("%s %s already exists", kind, newName)
+		}
+		if name == oldName && index < 0 {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("%s %s not found", kind, oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+
+	refMap := map[string]string{oldName: newName}
+	var services, databases, groups map[string]string
+	switch kind {
+	case ResourceKindService:
+		bp.Services[index].Name = newName
+		services = refMap
+	case ResourceKindDatabase:
+		db := &bp.Databases[index]
+		db.Name = newName
 		for j := range db.ReadReplicas {
 			replica := &db.ReadReplicas[j]
-			// Check if replica name follows the pattern of referencing the parent database
-			if strings.HasPrefix(replica.Name, db.Name[:len(db.Name)-len(prefix)]) {
-				// Update replica name to match the new database name
-				oldDBName := db.Name[:len(db.Name)-len(prefix)]
-				if strings.HasPrefix(replica.Name, oldDBName) {
-					replica.Name = strings.Replace(replica.Name, oldDBName, db.Name, 1)
-				}
+			if replica.Name == oldName || strings.HasPrefix(replica.Name, oldName+"-") {
+				replica.Name = newName + strings.TrimPrefix(replica.Name, oldName)
 			}
 		}
+		databases = refMap
+	case ResourceKindEnvVarGroup:
+		bp.EnvVarGroups[index].Name = newName
+		groups = refMap
 	}
 
-	return prefixed
+	for i := range bp.Services {
+		rebaseEnvVars(bp.Services[i].EnvVars, services, databases, groups)
+	}
+	for i := range bp.EnvVarGroups {
+		rebaseEnvVars(bp.EnvVarGroups[i].EnvVars, services, databases, groups)
+	}
+
+	return nil
 }
 
-// PrefixBlueprintWithSeparator adds a prefix with a separator to all named resources
-func PrefixBlueprintWithSeparator(bp *Blueprint, prefix, separator string) *Blueprint {
-	if separator == "" {
-		separator = "-"
+// Rebase returns a copy of the blueprint with fromDatabase, fromService and fromGroup
+// references rewritten according to refMap (old name to new name)
+// Resource names themselves are not changed and unmapped references are left intact
+func (bp *Blueprint) Rebase(refMap map[string]string) *Blueprint {
+	rebased := CopyBlueprint(bp)
+
+	for i := range rebased.Services {
+		rebaseEnvVars(rebased.Services[i].EnvVars, refMap, refMap, refMap)
+	}
+	for i := range rebased.EnvVarGroups {
+		rebaseEnvVars(rebased.EnvVarGroups[i].EnvVars, refMap, refMap, refMap)
+	}
+
+	return rebased
+}
+
+// rebaseEnvVars rewrites fromService, fromDatabase and fromGroup references in place
+// using a separate old-to-new name map per kind; a nil map leaves that kind alone
+func rebaseEnvVars(envVars []EnvVar, services, databases, groups map[string]string) {
+	for i := range envVars {
+		envVar := &envVars[i]
+		if envVar.FromDatabase != nil {
+			if newName, exists := databases[envVar.FromDatabase.Name]; exists {
+				envVar.FromDatabase.Name = newName
+			}
+		}
+		if envVar.FromService != nil {
+			if newName, exists := services[envVar.FromService.Name]; exists {
+				envVar.FromService.Name = newName
+			}
+		}
+		if envVar.FromGroup != nil {
+			if newName, exists := groups[*envVar.FromGroup]; exists {
+				envVar.FromGroup = &newName
+			}
+		}
 	}
-	return PrefixBlueprint(bp, prefix+separator)
+}
+
+// MapEnvVars returns a copy of the blueprint with fn applied to every env var
+// in every service and environment group
+// fn receives copies, so changing pointed-to values does not affect the original
+func (bp *Blueprint) MapEnvVars(fn func(EnvVar) EnvVar) *Blueprint {
+	mapped := CopyBlueprint(bp)
+
+	mapEnvVars := func(envVars []EnvVar) {
+		for i := range envVars {
+			envVars[i] = fn(envVars[i])
+		}
+	}
+
+	for i := range mapped.Services {
+		mapEnvVars(mapped.Services[i].EnvVars)
+	}
+	for i := range mapped.EnvVarGroups {
+		mapEnvVars(mapped.EnvVarGroups[i].EnvVars)
+	}
+
+	return mapped
 }
 
 // GetAllResourceNames returns all resource names in a blueprint
@@ -419,6 +828,36 @@ func GetExternalReferences(bp *Blueprint) (services, databases, envGroups []stri
 	return services, databases, envGroups
 }
 
+// ExternalReferenceVars returns copies of the env vars that reference resources outside the blueprint
+// Service variables come first, followed by environment group variables, in declaration order
+func (bp *Blueprint) ExternalReferenceVars() []EnvVar {
+	var external []EnvVar
+
+	if bp == nil {
+		return external
+	}
+
+	collect := func(envVars []EnvVar) {
+		for _, envVar := range envVars {
+			switch {
+			case envVar.FromDatabase != nil && !bp.HasDatabase(envVar.FromDatabase.Name),
+				envVar.FromService != nil && !bp.HasService(envVar.FromService.Name),
+				envVar.FromGroup != nil && !bp.HasEnvVarGroup(*envVar.FromGroup):
+				external = append(external, cloneOf(envVar))
+			}
+		}
+	}
+
+	for _, service := range bp.Services {
+		collect(service.EnvVars)
+	}
+	for _, group := range bp.EnvVarGroups {
+		collect(group.EnvVars)
+	}
+
+	return external
+}
+
 // GetServices returns all services from a blueprint (helper function)
 func (bp *Blueprint) GetServices() []Service {
 	if bp == nil {
@@ -482,6 +921,404 @@ func (bp *Blueprint) FindEnvVarGroup(name string) *EnvVarGroup {
 	return nil
 }
 
+// InlineGroup copies the variables of an environment group directly into a service
+// Nested FromGroup references in the group are skipped
+func (bp *Blueprint) InlineGroup(serviceName, groupName string) error {
+	service := bp.FindService(serviceName)
+	if service == nil {
+		return fmt.Errorf("service %s not found", serviceName)
+	}
+	group := bp.FindEnvVarGroup(groupName)
+	if group == nil {
+		return fmt.Errorf("environment group %s not found", groupName)
+	}
+
+	for _, envVar := range group.EnvVars {
+		if envVar.FromGroup != nil {
+			continue
+		}
+		service.EnvVars = append(service.EnvVars, cloneOf(envVar))
+	}
+
+	return nil
+}
+
+// AttachGroupToServices adds a fromGroup reference to each named service
+// Services that already include the group are skipped; nothing is changed if the group
+// or any of the services is missing
+func (bp *Blueprint) AttachGroupToServices(groupName string, serviceNames ...string) error {
+	if bp.FindEnvVarGroup(groupName) == nil {
+		return fmt.Errorf("environment group %s not found", groupName)
+	}
+
+	services := make([]*Service, 0, len(serviceNames))
+	for _, name := range serviceNames {
+		service := bp.FindService(name)
+		if service == nil {
+			return fmt.Errorf("service %s not found", name)
+		}
+		services = append(services, service)
+	}
+
+	for _, service := range services {
+		attached := false
+		for _, envVar := range service.EnvVars {
+			if envVar.FromGroup != nil && *envVar.FromGroup == groupName {
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			service.EnvVars = append(service.EnvVars, EnvFromGroup(groupName))
+		}
+	}
+
+	return nil
+}
+
+// EnvVarDiff compares the env vars of two services by key
+// added are keys only in b, removed are keys only in a, and changed are keys in both
+// whose value or source differs. fromGroup inclusions are identified as "fromGroup:<name>"
+func EnvVarDiff(a, b *Service) (added, removed, changed []string) {
+	before := envVarsByKey(a)
+	after := envVarsByKey(b)
+
+	for key, envVar := range after {
+		previous, exists := before[key]
+		switch {
+		case !exists:
+			added = append(added, key)
+		case !valuesEqual(reflect.ValueOf(previous), reflect.ValueOf(envVar)):
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, exists := after[key]; !exists {
+			removed = append(removed, key)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return added, removed, changed
+}
+
+// envVarsByKey indexes a service's env vars by key, with group inclusions under fromGroup:<name>
+func envVarsByKey(service *Service) map[string]EnvVar {
+	envVars := make(map[string]EnvVar)
+	if service == nil {
+		return envVars
+	}
+	for _, envVar := range service.EnvVars {
+		switch {
+		case envVar.Key != nil:
+			envVars[*envVar.Key] = envVar
+		case envVar.FromGroup != nil:
+			envVars["fromGroup:"+*envVar.FromGroup] = envVar
+		}
+	}
+	return envVars
+}
+
+// HasService reports whether a service with the given name exists
+func (bp *Blueprint) HasService(name string) bool {
+	return bp.FindService(name) != nil
+}
+
+// HasDatabase reports whether a database with the given name exists
+func (bp *Blueprint) HasDatabase(name string) bool {
+	return bp.FindDatabase(name) != nil
+}
+
+// HasEnvVarGroup reports whether an environment variable group with the given name exists
+func (bp *Blueprint) HasEnvVarGroup(name string) bool {
+	return bp.FindEnvVarGroup(name) != nil
+}
+
+// RemoveService removes the named service, reporting whether it existed
+// Env vars referencing it are left intact and become external references;
+// use RemoveWithReferences to drop them as well
+func (bp *Blueprint) RemoveService(name string) bool {
+	if bp == nil {
+		return false
+	}
+	for i, service := range bp.Services {
+		if service.Name == name {
+			bp.Services = append(bp.Services[:i], bp.Services[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDatabase removes the named database, reporting whether it existed
+// Env vars referencing it are left intact, as with RemoveService
+func (bp *Blueprint) RemoveDatabase(name string) bool {
+	if bp == nil {
+		return false
+	}
+	for i, db := range bp.Databases {
+		if db.Name == name {
+			bp.Databases = append(bp.Databases[:i], bp.Databases[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveEnvVarGroup removes the named environment group, reporting whether it existed
+// fromGroup entries referencing it are left intact, as with RemoveService
+func (bp *Blueprint) RemoveEnvVarGroup(name string) bool {
+	if bp == nil {
+		return false
+	}
+	for i, group := range bp.EnvVarGroups {
+		if group.Name == name {
+			bp.EnvVarGroups = append(bp.EnvVarGroups[:i], bp.EnvVarGroups[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveWithReferences removes the named resource and every env var in the remaining
+// services and groups that referenced it, reporting whether the resource existed
+// Nothing is changed when the resource does not exist
+func (bp *Blueprint) RemoveWithReferences(kind ResourceKind, name string) bool {
+	var removed bool
+	switch kind {
+	case ResourceKindService:
+		removed = bp.RemoveService(name)
+	case ResourceKindDatabase:
+		removed = bp.RemoveDatabase(name)
+	case ResourceKindEnvVarGroup:
+		removed = bp.RemoveEnvVarGroup(name)
+	}
+	if !removed {
+		return false
+	}
+
+	references := func(envVar EnvVar) bool {
+		switch kind {
+		case ResourceKindService:
+			return envVar.FromService != nil && envVar.FromService.Name == name
+		case ResourceKindDatabase:
+			return envVar.FromDatabase != nil && envVar.FromDatabase.Name == name
+		default:
+			return envVar.FromGroup != nil && *envVar.FromGroup == name
+		}
+	}
+	dropReferences := func(envVars []EnvVar) []EnvVar {
+		var kept []EnvVar
+		for _, envVar := range envVars {
+			if !references(envVar) {
+				kept = append(kept, envVar)
+			}
+		}
+		return kept
+	}
+
+	for i := range bp.Services {
+		bp.Services[i].EnvVars = dropReferences(bp.Services[i].EnvVars)
+	}
+	for i := range bp.EnvVarGroups {
+		bp.EnvVarGroups[i].EnvVars = dropReferences(bp.EnvVarGroups[i].EnvVars)
+	}
+
+	return true
+}
+
+// NormalizeKeyValueType replaces the deprecated redis service type with keyvalue
+func (s *Service) NormalizeKeyValueType() {
+	if s.Type == ServiceTypeRedis {
+		s.Type = ServiceTypeKeyValue
+	}
+}
+
+// NormalizeKeyValueTypes replaces the deprecated redis service type with keyvalue on every service
+func (bp *Blueprint) NormalizeKeyValueTypes() {
+	for i := range bp.Services {
+		bp.Services[i].NormalizeKeyValueType()
+	}
+}
+
+// SelectByTag returns a copy of the blueprint containing only services with the given tag
+// Databases and environment groups are kept as-is
+func (bp *Blueprint) SelectByTag(tag string) *Blueprint {
+	selected := CopyBlueprint(bp)
+
+	var services []Service
+	for _, service := range selected.Services {
+		for _, t := range service.Tags {
+			if t == tag {
+				services = append(services, service)
+				break
+			}
+		}
+	}
+	selected.Services = services
+
+	return selected
+}
+
+// Minimize returns a copy of the blueprint reduced to the named service and the services,
+// databases and environment groups it references transitively
+func (bp *Blueprint) Minimize(serviceName string) (*Blueprint, error) {
+	if bp.FindService(serviceName) == nil {
+		return nil, fmt.Errorf("service %s not found", serviceName)
+	}
+
+	services := map[string]bool{serviceName: true}
+	databases := make(map[string]bool)
+	envGroups := make(map[string]bool)
+
+	// Walk references until no new resources are reached
+	queue := bp.FindService(serviceName).EnvVars
+	for len(queue) > 0 {
+		envVar := queue[0]
+		queue = queue[1:]
+
+		switch {
+		case envVar.FromDatabase != nil:
+			databases[envVar.FromDatabase.Name] = true
+		case envVar.FromService != nil && !services[envVar.FromService.Name]:
+			services[envVar.FromService.Name] = true
+			if service := bp.FindService(envVar.FromService.Name); service != nil {
+				queue = append(queue, service.EnvVars...)
+			}
+		case envVar.FromGroup != nil && !envGroups[*envVar.FromGroup]:
+			envGroups[*envVar.FromGroup] = true
+			if group := bp.FindEnvVarGroup(*envVar.FromGroup); group != nil {
+				queue = append(queue, group.EnvVars...)
+			}
+		}
+	}
+
+	minimized := CopyBlueprint(bp)
+	minimized.Services = filterByName(minimized.Services, services, func(s Service) string { return s.Name })
+	minimized.Databases = filterByName(minimized.Databases, databases, func(db Database) string { return db.Name })
+	minimized.EnvVarGroups = filterByName(minimized.EnvVarGroups, envGroups, func(g EnvVarGroup) string { return g.Name })
+
+	return minimized, nil
+}
+
+// filterByName keeps the items whose name is in keep, preserving order
+func filterByName[T any](items []T, keep map[string]bool, name func(T) string) []T {
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep[name(item)] {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// FindOrphans returns databases and environment groups that nothing in the blueprint references
+func (bp *Blueprint) FindOrphans() (databases, envGroups []string) {
+	if bp == nil {
+		return nil, nil
+	}
+
+	referencedDatabases := make(map[string]bool)
+	referencedEnvGroups := make(map[string]bool)
+
+	collectReferences := func(envVars []EnvVar) {
+		for _, envVar := range envVars {
+			if envVar.FromDatabase != nil {
+				referencedDatabases[envVar.FromDatabase.Name] = true
+			}
+			if envVar.FromGroup != nil {
+				referencedEnvGroups[*envVar.FromGroup] = true
+			}
+		}
+	}
+
+	for _, service := range bp.Services {
+		collectReferences(service.EnvVars)
+	}
+	for _, group := range bp.EnvVarGroups {
+		collectReferences(group.EnvVars)
+	}
+
+	for _, db := range bp.Databases {
+		if !referencedDatabases[db.Name] {
+			databases = append(databases, db.Name)
+		}
+	}
+	for _, group := range bp.EnvVarGroups {
+		if !referencedEnvGroups[group.Name] {
+			envGroups = append(envGroups, group.Name)
+		}
+	}
+
+	return databases, envGroups
+}
+
+// PruneOrphans removes the databases and environment groups reported by FindOrphans
+// Services are never removed
+func (bp *Blueprint) PruneOrphans() (removedDatabases, removedGroups []string) {
+	removedDatabases, removedGroups = bp.FindOrphans()
+	if len(removedDatabases) == 0 && len(removedGroups) == 0 {
+		return removedDatabases, removedGroups
+	}
+
+	orphanedDatabases := make(map[string]bool)
+	for _, name := range removedDatabases {
+		orphanedDatabases[name] = true
+	}
+	orphanedGroups := make(map[string]bool)
+	for _, name := range removedGroups {
+		orphanedGroups[name] = true
+	}
+
+	var databases []Database
+	for _, db := range bp.Databases {
+		if !orphanedDatabases[db.Name] {
+			databases = append(databases, db)
+		}
+	}
+	bp.Databases = databases
+
+	var groups []EnvVarGroup
+	for _, group := range bp.EnvVarGroups {
+		if !orphanedGroups[group.Name] {
+			groups = append(groups, group)
+		}
+	}
+	bp.EnvVarGroups = groups
+
+	return removedDatabases, removedGroups
+}
+
+// FindAvailableName returns base if no resource of the given kind uses it,
+// otherwise the first free name of the form base-2, base-3, ...
+func FindAvailableName(base string, bp *Blueprint, kind ResourceKind) string {
+	existingNames := make(map[string]bool)
+
+	if bp != nil {
+		switch kind {
+		case ResourceKindService:
+			for _, service := range bp.Services {
+				existingNames[service.Name] = true
+			}
+		case ResourceKindDatabase:
+			for _, db := range bp.Databases {
+				existingNames[db.Name] = true
+			}
+		case ResourceKindEnvVarGroup:
+			for _, group := range bp.EnvVarGroups {
+				existingNames[group.Name] = true
+			}
+		}
+	}
+
+	if !existingNames[base] {
+		return base
+	}
+	return findAvailableName(base, existingNames)
+}
+
 // findAvailableName generates a unique name by appending a number
 func findAvailableName(baseName string, existingNames map[string]bool) string {
 	for i := 2; ; i++ {
@@ -490,4 +1327,4 @@ func findAvailableName(baseName string, existingNames map[string]bool) string {
 			return candidate
 		}
 	}
-}
\ No newline at end of file
+}
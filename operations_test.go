@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestMergeBlueprints(t *testing.T) {
@@ -128,6 +130,54 @@ func TestMergeBlueprints(t *testing.T) {
 	}
 }
 
+func TestMergeBlueprintsTracked(t *testing.T) {
+	layers := map[string]*Blueprint{
+		"teams/backend/render.yaml": {
+			Services:  []Service{{Name: "api", Type: ServiceTypeWeb}},
+			Databases: []Database{{Name: "main-db"}},
+		},
+		"shared/render.yaml": {
+			Services:     []Service{{Name: "worker", Type: ServiceTypeWorker}},
+			EnvVarGroups: []EnvVarGroup{{Name: "shared"}},
+		},
+	}
+
+	merged, provenance, err := MergeBlueprintsTracked(layers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedProvenance := map[string]string{
+		"api":     "teams/backend/render.yaml",
+		"main-db": "teams/backend/render.yaml",
+		"worker":  "shared/render.yaml",
+		"shared":  "shared/render.yaml",
+	}
+	if !reflect.DeepEqual(provenance, expectedProvenance) {
+		t.Errorf("provenance mismatch\nExpected: %v\nGot: %v", expectedProvenance, provenance)
+	}
+
+	// Layers are merged in lexical order of their names
+	services, databases, envGroups := GetAllResourceNames(merged)
+	if !reflect.DeepEqual(services, []string{"worker", "api"}) {
+		t.Errorf("unexpected merged services: %v", services)
+	}
+	if !reflect.DeepEqual(databases, []string{"main-db"}) {
+		t.Errorf("unexpected merged databases: %v", databases)
+	}
+	if !reflect.DeepEqual(envGroups, []string{"shared"}) {
+		t.Errorf("unexpected merged env groups: %v", envGroups)
+	}
+
+	// Conflicts between layers still fail
+	layers["teams/frontend/render.yaml"] = &Blueprint{
+		Services: []Service{{Name: "api", Type: ServiceTypeWeb}},
+	}
+	if _, _, err := MergeBlueprintsTracked(layers); err == nil {
+		t.Errorf("expected conflict error but got none")
+	}
+}
+
 func TestCopyBlueprint(t *testing.T) {
 	original := &Blueprint{
 		Services: []Service{
@@ -165,6 +215,16 @@ func TestCopyBlueprint(t *testing.T) {
 		t.Errorf("modifying copy affected original env groups")
 	}
 
+	// Test that nested values are not shared
+	*copied.Services[0].EnvVars[0].Value = "development"
+	*copied.Databases[0].Plan = PlanPro8GB
+	if *original.Services[0].EnvVars[0].Value != "production" {
+		t.Errorf("modifying copy affected original service env vars")
+	}
+	if *original.Databases[0].Plan != PlanBasic1GB {
+		t.Errorf("modifying copy affected original database plan")
+	}
+
 	// Test copying nil blueprint
 	nilCopy := CopyBlueprint(nil)
 	expected := &Blueprint{}
@@ -173,6 +233,120 @@ func TestCopyBlueprint(t *testing.T) {
 	}
 }
 
+func TestBlueprintEqual(t *testing.T) {
+	newBlueprint := func(plan Plan) *Blueprint {
+		api := NewWebService("api", RuntimeNode).
+			WithPlan(plan).
+			WithEnvVars(EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyConnectionString))
+		return NewBlueprint().
+			WithServices(api).
+			WithDatabases(NewDatabase("main-db").WithPlan(PlanBasic1GB))
+	}
+
+	if !newBlueprint(PlanStarter).Equal(newBlueprint(PlanStarter)) {
+		t.Errorf("identical blueprints should be equal")
+	}
+	if newBlueprint(PlanStarter).Equal(newBlueprint(PlanStandard)) {
+		t.Errorf("blueprints with different plans should not be equal")
+	}
+
+	// Empty and nil collections are equivalent
+	if !NewBlueprint().Equal(&Blueprint{}) {
+		t.Errorf("empty and zero-value blueprints should be equal")
+	}
+
+	var nilBP *Blueprint
+	if !nilBP.Equal(nil) {
+		t.Errorf("nil blueprints should be equal")
+	}
+	if nilBP.Equal(&Blueprint{}) || (&Blueprint{}).Equal(nil) {
+		t.Errorf("nil and non-nil blueprints should not be equal")
+	}
+}
+
+func TestBlueprintHash(t *testing.T) {
+	api := func() ServiceBuilder {
+		return NewWebService("api", RuntimeNode).WithPlan(PlanStarter).WithEnv("NODE_ENV", "production")
+	}
+	worker := func() ServiceBuilder {
+		return NewBackgroundWorker("worker", RuntimePython).WithPlan(PlanStarter)
+	}
+
+	original := NewBlueprint().
+		WithServices(api(), worker()).
+		WithDatabases(NewDatabase("main-db"), NewDatabase("analytics-db"))
+	reordered := NewBlueprint().
+		WithServices(worker(), api()).
+		WithDatabases(NewDatabase("analytics-db"), NewDatabase("main-db"))
+
+	originalHash, err := original.Hash()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reorderedHash, err := reordered.Hash()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if originalHash != reorderedHash {
+		t.Errorf("reordered blueprints should hash identically: %s != %s", originalHash, reorderedHash)
+	}
+	if len(originalHash) != 64 {
+		t.Errorf("expected a hex SHA-256 digest, got %q", originalHash)
+	}
+
+	// Hashing must not reorder the original
+	if original.Services[0].Name != "api" {
+		t.Errorf("Hash() modified the original service order")
+	}
+
+	// Reformatted YAML for the same content hashes identically
+	var loaded Blueprint
+	err = yaml.Unmarshal([]byte(`
+databases:
+  - {name: main-db}
+  - name: analytics-db
+services:
+  - name: worker
+    type: worker
+    runtime: python
+    plan: starter
+  - type: web
+    name: api
+    runtime: node
+    plan: starter
+    envVars:
+      - key: NODE_ENV
+        value: production
+`), &loaded)
+	if err != nil {
+		t.Fatalf("failed to load YAML: %v", err)
+	}
+	loadedHash, err := loaded.Hash()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loadedHash != originalHash {
+		t.Errorf("reformatted YAML should hash identically: %s != %s", loadedHash, originalHash)
+	}
+
+	// A changed plan produces a different hash
+	changed := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).WithPlan(PlanStandard).WithEnv("NODE_ENV", "production"), worker()).
+		WithDatabases(NewDatabase("main-db"), NewDatabase("analytics-db"))
+	changedHash, err := changed.Hash()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changedHash == originalHash {
+		t.Errorf("blueprints with different plans should hash differently")
+	}
+
+	var nilBP *Blueprint
+	if _, err := nilBP.Hash(); err == nil {
+		t.Errorf("expected error hashing nil blueprint")
+	}
+}
+
 func TestValidateBlueprint(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -887,6 +1061,816 @@ func TestBlueprintMethods(t *testing.T) {
 	}
 }
 
+func TestInlineGroup(t *testing.T) {
+	newBlueprint := func() *Blueprint {
+		api := NewWebService("api", RuntimeNode).WithEnv("NODE_ENV", "production")
+		shared := NewEnvVarGroup("shared").
+			WithEnv("LOG_LEVEL", "info").
+			WithSecret("API_KEY").
+			WithGenerated("SESSION_SECRET").
+			WithEnvVars(EnvFromGroup("base"))
+		return NewBlueprint().WithServices(api).WithEnvVarGroups(shared)
+	}
+
+	bp := newBlueprint()
+	if err := bp.InlineGroup("api", "shared"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []EnvVar{
+		Env("NODE_ENV", "production"),
+		Env("LOG_LEVEL", "info"),
+		EnvSecret("API_KEY"),
+		EnvGenerated("SESSION_SECRET"),
+	}
+	api := bp.FindService("api")
+	if len(api.EnvVars) != len(expected) {
+		t.Fatalf("expected %d env vars, got %d", len(expected), len(api.EnvVars))
+	}
+	for i, envVar := range expected {
+		if !envVarsEqual(api.EnvVars[i], envVar) || !reflect.DeepEqual(api.EnvVars[i], envVar) {
+			t.Errorf("env var %d mismatch\nExpected: %+v\nGot: %+v", i, envVar, api.EnvVars[i])
+		}
+	}
+
+	// Inlined vars do not share pointers with the group
+	*api.EnvVars[1].Value = "debug"
+	if *bp.FindEnvVarGroup("shared").EnvVars[0].Value != "info" {
+		t.Errorf("modifying inlined var affected the group")
+	}
+
+	// Missing resources are reported
+	if err := newBlueprint().InlineGroup("missing", "shared"); err == nil {
+		t.Errorf("expected error for missing service")
+	}
+	if err := newBlueprint().InlineGroup("api", "missing"); err == nil {
+		t.Errorf("expected error for missing group")
+	}
+}
+
+func TestBlueprintHasMethods(t *testing.T) {
+	bp := &Blueprint{
+		Services:     []Service{{Name: "api", Type: ServiceTypeWeb}},
+		Databases:    []Database{{Name: "main-db"}},
+		EnvVarGroups: []EnvVarGroup{{Name: "shared"}},
+	}
+
+	if !bp.HasService("api") {
+		t.Errorf("HasService('api') should be true")
+	}
+	if bp.HasService("worker") {
+		t.Errorf("HasService('worker') should be false")
+	}
+	if !bp.HasDatabase("main-db") {
+		t.Errorf("HasDatabase('main-db') should be true")
+	}
+	if bp.HasDatabase("api") {
+		t.Errorf("HasDatabase('api') should be false")
+	}
+	if !bp.HasEnvVarGroup("shared") {
+		t.Errorf("HasEnvVarGroup('shared') should be true")
+	}
+	if bp.HasEnvVarGroup("secrets") {
+		t.Errorf("HasEnvVarGroup('secrets') should be false")
+	}
+
+	// Test methods with nil blueprint
+	var nilBP *Blueprint
+
+	if nilBP.HasService("api") {
+		t.Errorf("nil blueprint HasService() should be false")
+	}
+	if nilBP.HasDatabase("main-db") {
+		t.Errorf("nil blueprint HasDatabase() should be false")
+	}
+	if nilBP.HasEnvVarGroup("shared") {
+		t.Errorf("nil blueprint HasEnvVarGroup() should be false")
+	}
+}
+
+func TestPruneOrphans(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).
+				WithEnvVars(
+					EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyConnectionString),
+					EnvVar{FromGroup: stringPtr("shared")},
+				),
+		).
+		WithDatabases(NewDatabase("main-db"), NewDatabase("legacy-db")).
+		WithEnvVarGroups(NewEnvVarGroup("shared"), NewEnvVarGroup("unused"))
+
+	removedDatabases, removedGroups := bp.PruneOrphans()
+
+	if !slicesEqual(removedDatabases, []string{"legacy-db"}) {
+		t.Errorf("expected legacy-db to be removed, got %v", removedDatabases)
+	}
+	if !slicesEqual(removedGroups, []string{"unused"}) {
+		t.Errorf("expected unused to be removed, got %v", removedGroups)
+	}
+
+	if !bp.HasDatabase("main-db") || bp.HasDatabase("legacy-db") {
+		t.Errorf("expected only main-db to remain, got %+v", bp.Databases)
+	}
+	if !bp.HasEnvVarGroup("shared") || bp.HasEnvVarGroup("unused") {
+		t.Errorf("expected only shared to remain, got %+v", bp.EnvVarGroups)
+	}
+	if !bp.HasService("api") {
+		t.Errorf("services should never be pruned")
+	}
+
+	// Pruning again finds nothing
+	removedDatabases, removedGroups = bp.PruneOrphans()
+	if len(removedDatabases) != 0 || len(removedGroups) != 0 {
+		t.Errorf("expected nothing to prune, got %v %v", removedDatabases, removedGroups)
+	}
+}
+
+func TestPrefixBlueprintWithOptions(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).
+				WithDisk("api-data", "/var/data", 10).
+				WithEnvVars(EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyConnectionString)),
+		).
+		WithDatabases(NewDatabase("main-db").WithReadReplicas("main-db-replica"))
+
+	t.Run("lowercases mixed-case prefix", func(t *testing.T) {
+		result := PrefixBlueprintWithOptions(bp, "Staging", PrefixOptions{Separator: "-", Lowercase: true})
+
+		if result.Services[0].Name != "staging-api" {
+			t.Errorf("expected service name staging-api, got %s", result.Services[0].Name)
+		}
+		if result.Databases[0].Name != "staging-main-db" {
+			t.Errorf("expected database name staging-main-db, got %s", result.Databases[0].Name)
+		}
+		if ref := result.Services[0].EnvVars[0].FromDatabase.Name; ref != "staging-main-db" {
+			t.Errorf("expected reference to staging-main-db, got %s", ref)
+		}
+		if replica := result.Databases[0].ReadReplicas[0].Name; replica != "staging-main-db-replica" {
+			t.Errorf("expected replica name staging-main-db-replica, got %s", replica)
+		}
+		if disk := result.Services[0].Disk.Name; disk != "api-data" {
+			t.Errorf("disk names should be unchanged by default, got %s", disk)
+		}
+	})
+
+	t.Run("prefixes disk names", func(t *testing.T) {
+		result := PrefixBlueprintWithOptions(bp, "pr-42", PrefixOptions{Separator: "-", PrefixDiskNames: true})
+
+		if disk := result.Services[0].Disk.Name; disk != "pr-42-api-data" {
+			t.Errorf("expected disk name pr-42-api-data, got %s", disk)
+		}
+		if bp.Services[0].Disk.Name != "api-data" {
+			t.Errorf("original blueprint was modified: %s", bp.Services[0].Disk.Name)
+		}
+	})
+
+	t.Run("prefixes only replicas named after the database", func(t *testing.T) {
+		bp := NewBlueprint().WithDatabases(NewDatabase("main").WithReadReplicas("main-replica", "maintenance"))
+		result := PrefixBlueprintWithOptions(bp, "staging", PrefixOptions{Separator: "-"})
+
+		replicas := []string{result.Databases[0].ReadReplicas[0].Name, result.Databases[0].ReadReplicas[1].Name}
+		if expected := []string{"staging-main-replica", "maintenance"}; !slicesEqual(replicas, expected) {
+			t.Errorf("expected replicas %v, got %v", expected, replicas)
+		}
+	})
+}
+
+func TestSelectByTag(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithTags("production", "staging"),
+			NewBackgroundWorker("debug-worker", RuntimeNode).WithTags("staging"),
+			NewStaticSite("docs").WithTags("production"),
+			NewKeyValueService("cache"),
+		).
+		WithDatabases(NewDatabase("main-db"))
+
+	selected := bp.SelectByTag("production")
+
+	services, databases, _ := GetAllResourceNames(selected)
+	if !slicesEqual(services, []string{"api", "docs"}) {
+		t.Errorf("expected services [api docs], got %v", services)
+	}
+	if !slicesEqual(databases, []string{"main-db"}) {
+		t.Errorf("expected databases to be kept, got %v", databases)
+	}
+	if len(bp.Services) != 4 {
+		t.Errorf("original blueprint was modified: %d services", len(bp.Services))
+	}
+
+	yamlStr, err := selected.ToYAMLString()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	for _, unexpected := range []string{"tags", "production"} {
+		if strings.Contains(yamlStr, unexpected) {
+			t.Errorf("expected tags to be stripped from YAML, found %q in:\n%s", unexpected, yamlStr)
+		}
+	}
+}
+
+func TestNormalizeKeyValueTypes(t *testing.T) {
+	var bp Blueprint
+	err := yaml.Unmarshal([]byte(`services:
+  - name: cache
+    type: redis
+    ipAllowList: []
+    maxmemoryPolicy: allkeys-lru
+  - name: api
+    type: web
+    runtime: node
+`), &bp)
+	if err != nil {
+		t.Fatalf("failed to parse YAML: %v", err)
+	}
+
+	if errors := ValidateBlueprint(&bp); len(errors) != 0 {
+		t.Errorf("redis services should validate without a runtime, got %v", errors)
+	}
+
+	bp.NormalizeKeyValueTypes()
+
+	if bp.Services[0].Type != ServiceTypeKeyValue {
+		t.Errorf("expected type %q, got %q", ServiceTypeKeyValue, bp.Services[0].Type)
+	}
+	if bp.Services[1].Type != ServiceTypeWeb {
+		t.Errorf("expected other services to be unchanged, got %q", bp.Services[1].Type)
+	}
+
+	yamlStr, err := bp.ToYAMLString()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	for _, expected := range []string{"type: keyvalue", "maxmemoryPolicy: allkeys-lru"} {
+		if !strings.Contains(yamlStr, expected) {
+			t.Errorf("expected %q in YAML:\n%s", expected, yamlStr)
+		}
+	}
+}
+
+func TestExternalReferenceVars(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).
+				WithEnvVars(
+					Env("NODE_ENV", "production"),
+					EnvDatabaseURL("main-db"),
+					EnvFromDatabase("ANALYTICS_URL", "analytics-db", DatabasePropertyConnectionString),
+					EnvFromService("AUTH_HOST", "auth", ServiceTypePServ, ServicePropertyHost),
+					EnvVar{FromGroup: stringPtr("platform-secrets")},
+				),
+		).
+		WithDatabases(NewDatabase("main-db")).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("LOG_LEVEL", "info"))
+
+	vars := bp.ExternalReferenceVars()
+	expected := []EnvVar{
+		EnvFromDatabase("ANALYTICS_URL", "analytics-db", DatabasePropertyConnectionString),
+		EnvFromService("AUTH_HOST", "auth", ServiceTypePServ, ServicePropertyHost),
+		{FromGroup: stringPtr("platform-secrets")},
+	}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, vars)
+	}
+
+	// The returned vars agree with GetExternalReferences
+	services, databases, envGroups := GetExternalReferences(bp)
+	if !slicesEqual(services, []string{vars[1].FromService.Name}) ||
+		!slicesEqual(databases, []string{vars[0].FromDatabase.Name}) ||
+		!slicesEqual(envGroups, []string{*vars[2].FromGroup}) {
+		t.Errorf("expected vars to match external references %v %v %v", services, databases, envGroups)
+	}
+
+	// Returned vars are copies
+	vars[0].FromDatabase.Name = "changed"
+	if bp.Services[0].EnvVars[2].FromDatabase.Name != "analytics-db" {
+		t.Errorf("expected returned vars to be independent of the blueprint")
+	}
+}
+
+func TestMergeBlueprintsDedupIdentical(t *testing.T) {
+	sharedDB := func() *Database {
+		return NewDatabase("shared-db").WithPlan(PlanBasic1GB).WithPostgreSQL(PostgreSQL16)
+	}
+
+	api := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode)).
+		WithDatabases(sharedDB())
+	worker := NewBlueprint().
+		WithServices(NewBackgroundWorker("worker", RuntimeNode)).
+		WithDatabases(sharedDB())
+
+	t.Run("identical resources collapse", func(t *testing.T) {
+		merged, err := MergeBlueprintsWithStrategy(api, worker, MergeStrategyDedupIdentical)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(merged.Databases) != 1 || merged.Databases[0].Name != "shared-db" {
+			t.Errorf("expected a single shared-db, got %+v", merged.Databases)
+		}
+		if len(merged.Services) != 2 {
+			t.Errorf("expected both services, got %d", len(merged.Services))
+		}
+
+		conflicts := FindConflictsDetailed(api, worker)
+		expected := []Conflict{{Kind: ResourceKindDatabase, Name: "shared-db", Identical: true}}
+		if !reflect.DeepEqual(conflicts, expected) {
+			t.Errorf("expected %+v, got %+v", expected, conflicts)
+		}
+	})
+
+	t.Run("strict merge still fails", func(t *testing.T) {
+		if _, err := MergeBlueprints(api, worker); err == nil {
+			t.Errorf("expected strict merge to fail on shared-db")
+		}
+	})
+
+	t.Run("differing resources fail", func(t *testing.T) {
+		bigger := NewBlueprint().WithDatabases(sharedDB().WithPlan(PlanPro8GB))
+
+		_, err := MergeBlueprintsWithStrategy(api, bigger, MergeStrategyDedupIdentical)
+		if err == nil || !strings.Contains(err.Error(), "database name conflict: shared-db") {
+			t.Errorf("expected a shared-db conflict, got %v", err)
+		}
+	})
+}
+
+func TestMergeBlueprintsSortByName(t *testing.T) {
+	layers := []*Blueprint{
+		NewBlueprint().
+			WithServices(NewWebService("web", RuntimeNode)).
+			WithDatabases(NewDatabase("users-db")),
+		NewBlueprint().
+			WithServices(NewBackgroundWorker("billing", RuntimeNode)).
+			WithEnvVarGroups(NewEnvVarGroup("shared")),
+		NewBlueprint().
+			WithServices(NewPrivateService("api", RuntimeGo)).
+			WithDatabases(NewDatabase("analytics-db")).
+			WithEnvVarGroups(NewEnvVarGroup("billing-settings")),
+	}
+
+	fold := func(order ...int) *Blueprint {
+		var merged *Blueprint
+		for _, i := range order {
+			var err error
+			merged, err = MergeBlueprintsWithStrategy(merged, layers[i], MergeStrategySortByName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return merged
+	}
+
+	first := fold(0, 1, 2)
+	second := fold(2, 0, 1)
+	third := fold(1, 2, 0)
+
+	if !reflect.DeepEqual(first, second) || !reflect.DeepEqual(first, third) {
+		t.Errorf("expected identical output regardless of layer order:\n%+v\n%+v\n%+v", first, second, third)
+	}
+
+	services, databases, envGroups := GetAllResourceNames(first)
+	if expected := []string{"api", "billing", "web"}; !reflect.DeepEqual(services, expected) {
+		t.Errorf("expected services %v, got %v", expected, services)
+	}
+	if expected := []string{"analytics-db", "users-db"}; !reflect.DeepEqual(databases, expected) {
+		t.Errorf("expected databases %v, got %v", expected, databases)
+	}
+	if expected := []string{"billing-settings", "shared"}; !reflect.DeepEqual(envGroups, expected) {
+		t.Errorf("expected groups %v, got %v", expected, envGroups)
+	}
+
+	// Without the flag the merge keeps fold order
+	unsorted, err := MergeBlueprints(layers[0], layers[2])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unsorted.Services[0].Name != "web" {
+		t.Errorf("expected fold order without sorting, got %s first", unsorted.Services[0].Name)
+	}
+}
+
+func TestFindAvailableName(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode), NewWebService("api-2", RuntimeNode)).
+		WithDatabases(NewDatabase("main-db"))
+
+	tests := []struct {
+		name     string
+		base     string
+		kind     ResourceKind
+		expected string
+	}{
+		{name: "free service name", base: "worker", kind: ResourceKindService, expected: "worker"},
+		{name: "colliding service name", base: "api", kind: ResourceKindService, expected: "api-3"},
+		{name: "colliding database name", base: "main-db", kind: ResourceKindDatabase, expected: "main-db-2"},
+		{name: "name used by another kind", base: "api", kind: ResourceKindDatabase, expected: "api"},
+		{name: "free group name", base: "main-db", kind: ResourceKindEnvVarGroup, expected: "main-db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindAvailableName(tt.base, bp, tt.kind); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRebase(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).WithEnvVars(
+			EnvFromDatabase("DATABASE_URL", "old-db", DatabasePropertyConnectionString),
+			EnvFromDatabase("ANALYTICS_URL", "analytics-db", DatabasePropertyConnectionString),
+			EnvFromGroup("old-shared"),
+		)).
+		WithEnvVarGroups(NewEnvVarGroup("settings").WithEnvVars(
+			EnvFromDatabase("DB_HOST", "old-db", DatabasePropertyHost),
+		))
+
+	rebased := bp.Rebase(map[string]string{
+		"old-db":     "new-db",
+		"old-shared": "new-shared",
+	})
+
+	envVars := rebased.Services[0].EnvVars
+	if envVars[0].FromDatabase.Name != "new-db" {
+		t.Errorf("expected mapped database reference new-db, got %s", envVars[0].FromDatabase.Name)
+	}
+	if envVars[1].FromDatabase.Name != "analytics-db" {
+		t.Errorf("expected unmapped database reference to stay analytics-db, got %s", envVars[1].FromDatabase.Name)
+	}
+	if *envVars[2].FromGroup != "new-shared" {
+		t.Errorf("expected mapped group reference new-shared, got %s", *envVars[2].FromGroup)
+	}
+	if name := rebased.EnvVarGroups[0].EnvVars[0].FromDatabase.Name; name != "new-db" {
+		t.Errorf("expected group reference to be rebased to new-db, got %s", name)
+	}
+
+	// Resource names and the original blueprint are unchanged
+	if rebased.Services[0].Name != "api" || rebased.EnvVarGroups[0].Name != "settings" {
+		t.Errorf("expected resource names to be unchanged")
+	}
+	if bp.Services[0].EnvVars[0].FromDatabase.Name != "old-db" {
+		t.Errorf("expected the original blueprint to be unchanged")
+	}
+}
+
+func TestAttachGroupToServices(t *testing.T) {
+	newBlueprint := func() *Blueprint {
+		return NewBlueprint().
+			WithServices(
+				NewWebService("api", RuntimeNode),
+				NewBackgroundWorker("worker", RuntimeNode),
+				NewPrivateService("internal", RuntimeNode).WithEnvVars(EnvFromGroup("shared")),
+			).
+			WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("LOG_LEVEL", "info"))
+	}
+
+	countGroupRefs := func(service *Service) int {
+		count := 0
+		for _, envVar := range service.EnvVars {
+			if envVar.FromGroup != nil && *envVar.FromGroup == "shared" {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("attaches and skips existing", func(t *testing.T) {
+		bp := newBlueprint()
+		if err := bp.AttachGroupToServices("shared", "api", "worker", "internal"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, name := range []string{"api", "worker", "internal"} {
+			if count := countGroupRefs(bp.FindService(name)); count != 1 {
+				t.Errorf("expected %s to include shared once, got %d", name, count)
+			}
+		}
+	})
+
+	tests := []struct {
+		name     string
+		group    string
+		services []string
+		expected string
+	}{
+		{"missing group", "other", []string{"api"}, "environment group other not found"},
+		{"missing service", "shared", []string{"api", "missing"}, "service missing not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := newBlueprint()
+			err := bp.AttachGroupToServices(tt.group, tt.services...)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+			if countGroupRefs(bp.FindService("api")) != 0 {
+				t.Errorf("expected no changes when attaching fails")
+			}
+		})
+	}
+}
+
+func TestEnvVarDiff(t *testing.T) {
+	staging := NewWebService("api", RuntimeNode).
+		WithEnv("LOG_LEVEL", "debug").
+		WithEnvVars(
+			EnvFromDatabase("DATABASE_URL", "staging-db", DatabasePropertyConnectionString),
+			EnvFromService("CACHE_HOST", "cache", ServiceTypeKeyValue, ServicePropertyHost),
+			EnvFromGroup("shared"),
+		).ToService()
+	production := NewWebService("api", RuntimeNode).
+		WithEnv("LOG_LEVEL", "info").
+		WithEnv("SENTRY_DSN", "https://sentry.example.com/1").
+		WithEnvVars(
+			EnvFromDatabase("DATABASE_URL", "prod-db", DatabasePropertyConnectionString),
+			EnvFromGroup("shared"),
+			EnvFromGroup("prod-secrets"),
+		).ToService()
+
+	added, removed, changed := EnvVarDiff(staging, production)
+
+	if expected := []string{"SENTRY_DSN", "fromGroup:prod-secrets"}; !reflect.DeepEqual(added, expected) {
+		t.Errorf("expected added %v, got %v", expected, added)
+	}
+	if expected := []string{"CACHE_HOST"}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("expected removed %v, got %v", expected, removed)
+	}
+	if expected := []string{"DATABASE_URL", "LOG_LEVEL"}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected changed %v, got %v", expected, changed)
+	}
+
+	added, removed, changed = EnvVarDiff(staging, staging)
+	if added != nil || removed != nil || changed != nil {
+		t.Errorf("expected no differences for identical services, got %v %v %v", added, removed, changed)
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithEnvVars(
+				EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyConnectionString),
+				EnvFromGroup("shared"),
+			),
+			NewPrivateService("auth", RuntimeGo),
+			NewBackgroundWorker("unrelated-worker", RuntimeNode).WithEnvVars(EnvFromGroup("worker-settings")),
+		).
+		WithDatabases(NewDatabase("main-db"), NewDatabase("analytics-db"), NewDatabase("cache-db")).
+		WithEnvVarGroups(
+			NewEnvVarGroup("shared").WithEnvVars(
+				EnvFromService("AUTH_HOST", "auth", ServiceTypePServ, ServicePropertyHost),
+				EnvFromDatabase("CACHE_DB_URL", "cache-db", DatabasePropertyConnectionString),
+			),
+			NewEnvVarGroup("worker-settings").WithEnv("QUEUE", "default"),
+		)
+
+	minimized, err := bp.Minimize("api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	services, databases, envGroups := GetAllResourceNames(minimized)
+	if expected := []string{"api", "auth"}; !reflect.DeepEqual(services, expected) {
+		t.Errorf("expected services %v, got %v", expected, services)
+	}
+	if expected := []string{"main-db", "cache-db"}; !reflect.DeepEqual(databases, expected) {
+		t.Errorf("expected databases %v, got %v", expected, databases)
+	}
+	if expected := []string{"shared"}; !reflect.DeepEqual(envGroups, expected) {
+		t.Errorf("expected groups %v, got %v", expected, envGroups)
+	}
+
+	// The original blueprint is untouched
+	if len(bp.Services) != 3 || len(bp.Databases) != 3 {
+		t.Errorf("expected the original blueprint to be unchanged")
+	}
+
+	if _, err := bp.Minimize("missing"); err == nil {
+		t.Errorf("expected an error for a missing service")
+	}
+}
+
+func TestWithDefaultAutoDeploy(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewBackgroundWorker("worker", RuntimeNode),
+			NewWebService("api", RuntimeNode).WithAutoDeploy(true),
+			NewKeyValueService("cache"),
+		).
+		WithDefaultAutoDeploy(false)
+
+	if autoDeploy := bp.Services[0].AutoDeploy; autoDeploy == nil || *autoDeploy {
+		t.Errorf("expected worker autoDeploy to default to false, got %v", autoDeploy)
+	}
+	if autoDeploy := bp.Services[1].AutoDeploy; autoDeploy == nil || !*autoDeploy {
+		t.Errorf("expected api to keep its explicit autoDeploy, got %v", autoDeploy)
+	}
+	if autoDeploy := bp.Services[2].AutoDeploy; autoDeploy != nil {
+		t.Errorf("expected key-value service to stay without autoDeploy, got %v", *autoDeploy)
+	}
+}
+
+func TestMapEnvVars(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).
+			WithEnv("log_level", "info").
+			WithEnvVars(EnvFromDatabase("database_url", "main-db", "connectionString"), EnvFromGroup("shared"))).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("region", "oregon"))
+
+	upper := bp.MapEnvVars(func(envVar EnvVar) EnvVar {
+		if envVar.Key != nil {
+			envVar.Key = stringPtr(strings.ToUpper(*envVar.Key))
+		}
+		return envVar
+	})
+
+	var keys []string
+	for _, envVar := range append(append([]EnvVar{}, upper.Services[0].EnvVars...), upper.EnvVarGroups[0].EnvVars...) {
+		if envVar.Key != nil {
+			keys = append(keys, *envVar.Key)
+		}
+	}
+	if expected := []string{"LOG_LEVEL", "DATABASE_URL", "REGION"}; !slicesEqual(keys, expected) {
+		t.Errorf("expected keys %v, got %v", expected, keys)
+	}
+
+	// References survive the transform
+	envVars := upper.Services[0].EnvVars
+	if envVars[1].FromDatabase == nil || envVars[1].FromDatabase.Name != "main-db" || envVars[1].FromDatabase.Property != "connectionString" {
+		t.Errorf("expected the fromDatabase reference to be preserved, got %+v", envVars[1].FromDatabase)
+	}
+	if envVars[2].FromGroup == nil || *envVars[2].FromGroup != "shared" {
+		t.Errorf("expected the fromGroup reference to be preserved, got %+v", envVars[2])
+	}
+
+	// The original is unchanged
+	if got := *bp.Services[0].EnvVars[0].Key; got != "log_level" {
+		t.Errorf("expected the original key to be unchanged, got %s", got)
+	}
+	if got := *bp.EnvVarGroups[0].EnvVars[0].Key; got != "region" {
+		t.Errorf("expected the original group key to be unchanged, got %s", got)
+	}
+}
+
+func TestRemoveResources(t *testing.T) {
+	newBlueprint := func() *Blueprint {
+		return NewBlueprint().
+			WithServices(
+				NewWebService("api", RuntimeNode).
+					WithDatabaseURL("main-db").
+					WithEnv("LOG_LEVEL", "info").
+					WithEnvVars(EnvFromGroup("shared")),
+				NewBackgroundWorker("worker", RuntimeNode),
+			).
+			WithDatabases(NewDatabase("main-db")).
+			WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("REGION", "oregon"))
+	}
+
+	t.Run("remove leaves references intact", func(t *testing.T) {
+		bp := newBlueprint()
+
+		if !bp.RemoveService("worker") || bp.HasService("worker") {
+			t.Error("expected worker to be removed")
+		}
+		if !bp.RemoveDatabase("main-db") || bp.HasDatabase("main-db") {
+			t.Error("expected main-db to be removed")
+		}
+		if !bp.RemoveEnvVarGroup("shared") || bp.HasEnvVarGroup("shared") {
+			t.Error("expected shared to be removed")
+		}
+		if bp.RemoveService("missing") || bp.RemoveDatabase("missing") || bp.RemoveEnvVarGroup("missing") {
+			t.Error("expected removing a missing resource to report false")
+		}
+		if got := len(bp.Services[0].EnvVars); got != 3 {
+			t.Errorf("expected api to keep its 3 env vars, got %d", got)
+		}
+	})
+
+	t.Run("remove with references", func(t *testing.T) {
+		bp := newBlueprint()
+
+		if !bp.RemoveWithReferences(ResourceKindDatabase, "main-db") {
+			t.Fatal("expected main-db to be removed")
+		}
+		if !bp.RemoveWithReferences(ResourceKindEnvVarGroup, "shared") {
+			t.Fatal("expected shared to be removed")
+		}
+
+		envVars := bp.Services[0].EnvVars
+		if len(envVars) != 1 || *envVars[0].Key != "LOG_LEVEL" {
+			t.Errorf("expected only LOG_LEVEL to remain, got %+v", envVars)
+		}
+		if bp.RemoveWithReferences(ResourceKindService, "missing") {
+			t.Error("expected removing a missing resource to report false")
+		}
+	})
+
+	t.Run("nil blueprint", func(t *testing.T) {
+		var bp *Blueprint
+		if bp.RemoveService("api") || bp.RemoveDatabase("main-db") || bp.RemoveEnvVarGroup("shared") || bp.RemoveWithReferences(ResourceKindService, "api") {
+			t.Error("expected nil blueprint removals to report false")
+		}
+	})
+}
+
+func TestRenameResources(t *testing.T) {
+	newBlueprint := func() *Blueprint {
+		return NewBlueprint().
+			WithServices(
+				NewWebService("api", RuntimeNode).
+					WithDatabaseURL("main-db").
+					WithEnvVars(EnvFromGroup("shared")),
+				NewBackgroundWorker("worker", RuntimeNode).
+					WithEnvVars(EnvFromService("API_HOST", "api", ServiceTypeWeb, ServicePropertyHost)),
+			).
+			WithDatabases(NewDatabase("main-db").WithReadReplicas("main-db-replica", "analytics")).
+			WithEnvVarGroups(NewEnvVarGroup("shared").WithEnvVars(EnvFromDatabase("DB_HOST", "main-db", DatabasePropertyHost)))
+	}
+
+	t.Run("service", func(t *testing.T) {
+		bp := newBlueprint()
+		if err := RenameService(bp, "api", "gateway"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if bp.Services[0].Name != "gateway" {
+			t.Errorf("expected the service to be renamed, got %s", bp.Services[0].Name)
+		}
+		if got := bp.Services[1].EnvVars[0].FromService.Name; got != "gateway" {
+			t.Errorf("expected the fromService reference to follow, got %s", got)
+		}
+	})
+
+	t.Run("database", func(t *testing.T) {
+		bp := newBlueprint()
+		if err := RenameDatabase(bp, "main-db", "primary-db"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		replicas := []string{bp.Databases[0].ReadReplicas[0].Name, bp.Databases[0].ReadReplicas[1].Name}
+		if expected := []string{"primary-db-replica", "analytics"}; !slicesEqual(replicas, expected) {
+			t.Errorf("expected replicas %v, got %v", expected, replicas)
+		}
+		if got := bp.Services[0].EnvVars[0].FromDatabase.Name; got != "primary-db" {
+			t.Errorf("expected the service reference to follow, got %s", got)
+		}
+		if got := bp.EnvVarGroups[0].EnvVars[0].FromDatabase.Name; got != "primary-db" {
+			t.Errorf("expected the group reference to follow, got %s", got)
+		}
+	})
+
+	t.Run("database replicas need a separator", func(t *testing.T) {
+		bp := NewBlueprint().
+			WithServices(
+				NewWebService("main", RuntimeNode),
+				NewBackgroundWorker("worker", RuntimeNode).
+					WithEnvVars(EnvFromService("MAIN_HOST", "main", ServiceTypeWeb, ServicePropertyHost)),
+			).
+			WithDatabases(NewDatabase("main").WithReadReplicas("main-replica", "maintenance"))
+		if err := RenameDatabase(bp, "main", "core"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		replicas := []string{bp.Databases[0].ReadReplicas[0].Name, bp.Databases[0].ReadReplicas[1].Name}
+		if expected := []string{"core-replica", "maintenance"}; !slicesEqual(replicas, expected) {
+			t.Errorf("expected replicas %v, got %v", expected, replicas)
+		}
+		if got := bp.Services[1].EnvVars[0].FromService.Name; got != "main" {
+			t.Errorf("expected the service reference to be left alone, got %s", got)
+		}
+	})
+
+	t.Run("env var group", func(t *testing.T) {
+		bp := newBlueprint()
+		if err := RenameEnvVarGroup(bp, "shared", "common"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if bp.EnvVarGroups[0].Name != "common" || *bp.Services[0].EnvVars[1].FromGroup != "common" {
+			t.Errorf("expected the group and its reference to be renamed, got %s and %s", bp.EnvVarGroups[0].Name, *bp.Services[0].EnvVars[1].FromGroup)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		bp := newBlueprint()
+		if err := RenameService(bp, "api", "worker"); err == nil {
+			t.Error("expected an error when the new name is taken")
+		}
+		if err := RenameDatabase(bp, "missing", "other"); err == nil {
+			t.Error("expected an error for an unknown database")
+		}
+		if err := RenameEnvVarGroup(nil, "shared", "common"); err == nil {
+			t.Error("expected an error for a nil blueprint")
+		}
+		if bp.Services[0].Name != "api" {
+			t.Errorf("expected failed renames to leave the blueprint unchanged, got %s", bp.Services[0].Name)
+		}
+	})
+}
+
 // Helper functions for tests
 
 func stringPtr(s string) *string {
@@ -905,6 +1889,10 @@ func runtimePtr(r Runtime) *Runtime {
 	return &r
 }
 
+func regionPtr(r Region) *Region {
+	return &r
+}
+
 func slicesEqual(a, b []string) bool {
 	// Handle nil vs empty slice cases
 	if len(a) == 0 && len(b) == 0 {
@@ -0,0 +1,63 @@
+package render
+
+// WebServiceOption configures a WebService in NewWebServiceWith
+type WebServiceOption func(*WebService)
+
+// NewWebServiceWith creates a WebService fully specified by options in one expression
+func NewWebServiceWith(name string, runtime Runtime, opts ...WebServiceOption) *WebService {
+	ws := NewWebService(name, runtime)
+	for _, opt := range opts {
+		opt(ws)
+	}
+	return ws
+}
+
+// WithPlanOpt sets the instance plan
+func WithPlanOpt(plan Plan) WebServiceOption {
+	return func(ws *WebService) { ws.WithPlan(plan) }
+}
+
+// WithRegionOpt sets the deployment region
+func WithRegionOpt(region Region) WebServiceOption {
+	return func(ws *WebService) { ws.WithRegion(region) }
+}
+
+// WithDomainsOpt adds custom domains
+func WithDomainsOpt(domains ...string) WebServiceOption {
+	return func(ws *WebService) { ws.WithDomains(domains...) }
+}
+
+// WithHealthCheckOpt sets the health check path
+func WithHealthCheckOpt(path string) WebServiceOption {
+	return func(ws *WebService) { ws.WithHealthCheck(path) }
+}
+
+// WithStartCommandOpt sets the start command
+func WithStartCommandOpt(cmd string) WebServiceOption {
+	return func(ws *WebService) { ws.WithStartCommand(cmd) }
+}
+
+// WithGitOpt sets the repository and optional branch
+func WithGitOpt(repo string, branch ...string) WebServiceOption {
+	return func(ws *WebService) { ws.WithGit(repo, branch...) }
+}
+
+// WithBuildOpt sets the build command
+func WithBuildOpt(buildCmd string) WebServiceOption {
+	return func(ws *WebService) { ws.WithBuild(buildCmd) }
+}
+
+// WithEnvOpt adds a plain environment variable
+func WithEnvOpt(key, value string) WebServiceOption {
+	return func(ws *WebService) { ws.WithEnv(key, value) }
+}
+
+// WithEnvVarsOpt adds environment variables
+func WithEnvVarsOpt(envVars ...EnvVar) WebServiceOption {
+	return func(ws *WebService) { ws.WithEnvVars(envVars...) }
+}
+
+// WithScalingOpt sets a fixed number of instances
+func WithScalingOpt(numInstances int) WebServiceOption {
+	return func(ws *WebService) { ws.WithScaling(numInstances) }
+}
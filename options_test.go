@@ -0,0 +1,50 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewWebServiceWith(t *testing.T) {
+	withOptions := NewWebServiceWith("api", RuntimeNode,
+		WithPlanOpt(PlanStandard),
+		WithRegionOpt(RegionFrankfurt),
+		WithDomainsOpt("api.example.com"),
+		WithHealthCheckOpt("/health"),
+		WithStartCommandOpt("npm start"),
+		WithGitOpt("https://github.com/example/api", "main"),
+		WithBuildOpt("npm ci"),
+		WithEnvOpt("NODE_ENV", "production"),
+		WithEnvVarsOpt(EnvDatabaseURL("main-db")),
+		WithScalingOpt(2),
+	)
+
+	fluent := NewWebService("api", RuntimeNode).
+		WithPlan(PlanStandard).
+		WithRegion(RegionFrankfurt).
+		WithDomains("api.example.com").
+		WithHealthCheck("/health").
+		WithStartCommand("npm start").
+		WithGit("https://github.com/example/api", "main").
+		WithBuild("npm ci").
+		WithEnv("NODE_ENV", "production").
+		WithEnvVars(EnvDatabaseURL("main-db")).
+		WithScaling(2)
+
+	if !reflect.DeepEqual(withOptions, fluent) {
+		t.Errorf("expected options to match fluent form:\n got  %+v\n want %+v", withOptions, fluent)
+	}
+
+	// Options can be shared without sharing state between services
+	shared := []WebServiceOption{WithPlanOpt(PlanStarter), WithEnvOpt("LOG_LEVEL", "info")}
+	first := NewWebServiceWith("first", RuntimeNode, shared...)
+	second := NewWebServiceWith("second", RuntimeNode, shared...)
+	first.WithEnv("EXTRA", "1")
+	if len(second.EnvVars) != 1 {
+		t.Errorf("expected second service to be unaffected, got %+v", second.EnvVars)
+	}
+	*first.Plan = PlanPro
+	if *second.Plan != PlanStarter {
+		t.Errorf("expected plans not to be shared, got %s", *second.Plan)
+	}
+}
@@ -0,0 +1,130 @@
+package render
+
+import "reflect"
+
+// BlueprintPatch records resource-level changes between two blueprints
+// Changed and added resources are stored whole; removed ones by name
+// Clearing blueprint-level preview settings cannot be expressed and is not recorded
+type BlueprintPatch struct {
+	SetServices             []Service     `yaml:"setServices,omitempty"`
+	RemoveServices          []string      `yaml:"removeServices,omitempty"`
+	SetDatabases            []Database    `yaml:"setDatabases,omitempty"`
+	RemoveDatabases         []string      `yaml:"removeDatabases,omitempty"`
+	SetEnvVarGroups         []EnvVarGroup `yaml:"setEnvVarGroups,omitempty"`
+	RemoveEnvVarGroups      []string      `yaml:"removeEnvVarGroups,omitempty"`
+	Previews                *Previews     `yaml:"previews,omitempty"`
+	PreviewsExpireAfterDays *int          `yaml:"previewsExpireAfterDays,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p *BlueprintPatch) IsEmpty() bool {
+	return p == nil || valuesEqual(reflect.ValueOf(*p), reflect.ValueOf(BlueprintPatch{}))
+}
+
+// GeneratePatch returns the patch that turns old into new when passed to ApplyPatch
+// Resources added in new are appended by ApplyPatch, so their relative order is kept
+// only when they come after the existing ones
+func GeneratePatch(old, new *Blueprint) *BlueprintPatch {
+	if old == nil {
+		old = &Blueprint{}
+	}
+	if new == nil {
+		new = &Blueprint{}
+	}
+
+	patch := &BlueprintPatch{}
+
+	patch.SetServices, patch.RemoveServices = diffResources(old.Services, new.Services, func(s Service) string { return s.Name })
+	patch.SetDatabases, patch.RemoveDatabases = diffResources(old.Databases, new.Databases, func(db Database) string { return db.Name })
+	patch.SetEnvVarGroups, patch.RemoveEnvVarGroups = diffResources(old.EnvVarGroups, new.EnvVarGroups, func(g EnvVarGroup) string { return g.Name })
+
+	if new.Previews != nil && !valuesEqual(reflect.ValueOf(old.Previews), reflect.ValueOf(new.Previews)) {
+		previews := *new.Previews
+		patch.Previews = &previews
+	}
+	if new.PreviewsExpireAfterDays != nil && !valuesEqual(reflect.ValueOf(old.PreviewsExpireAfterDays), reflect.ValueOf(new.PreviewsExpireAfterDays)) {
+		days := *new.PreviewsExpireAfterDays
+		patch.PreviewsExpireAfterDays = &days
+	}
+
+	return patch
+}
+
+// ApplyPatch returns a copy of bp with the patch applied
+// Set resources replace same-named ones in place or are appended
+func ApplyPatch(bp *Blueprint, patch *BlueprintPatch) *Blueprint {
+	patched := CopyBlueprint(bp)
+	if patch == nil {
+		return patched
+	}
+
+	patched.Services = applyResources(patched.Services, cloneOf(patch.SetServices), patch.RemoveServices, func(s Service) string { return s.Name })
+	patched.Databases = applyResources(patched.Databases, cloneOf(patch.SetDatabases), patch.RemoveDatabases, func(db Database) string { return db.Name })
+	patched.EnvVarGroups = applyResources(patched.EnvVarGroups, cloneOf(patch.SetEnvVarGroups), patch.RemoveEnvVarGroups, func(g EnvVarGroup) string { return g.Name })
+
+	if patch.Previews != nil {
+		previews := *patch.Previews
+		patched.Previews = &previews
+	}
+	if patch.PreviewsExpireAfterDays != nil {
+		days := *patch.PreviewsExpireAfterDays
+		patched.PreviewsExpireAfterDays = &days
+	}
+
+	return patched
+}
+
+// diffResources returns the resources of after that are new or changed, and the names only in before
+func diffResources[T any](before, after []T, name func(T) string) (set []T, removed []string) {
+	previous := make(map[string]T, len(before))
+	for _, item := range before {
+		previous[name(item)] = item
+	}
+	current := make(map[string]bool, len(after))
+
+	for _, item := range after {
+		current[name(item)] = true
+		old, exists := previous[name(item)]
+		if !exists || !valuesEqual(reflect.ValueOf(old), reflect.ValueOf(item)) {
+			set = append(set, cloneOf(item))
+		}
+	}
+	for _, item := range before {
+		if !current[name(item)] {
+			removed = append(removed, name(item))
+		}
+	}
+
+	return set, removed
+}
+
+// applyResources removes the named resources, then replaces or appends the set ones
+func applyResources[T any](items, set []T, removed []string, name func(T) string) []T {
+	remove := make(map[string]bool, len(removed))
+	for _, n := range removed {
+		remove[n] = true
+	}
+
+	result := make([]T, 0, len(items)+len(set))
+	for _, item := range items {
+		if !remove[name(item)] {
+			result = append(result, item)
+		}
+	}
+
+	for _, item := range set {
+		replaced := false
+		for i := range result {
+			if name(result[i]) == name(item) {
+				result[i] = item
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
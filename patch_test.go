@@ -0,0 +1,74 @@
+package render
+
+import "testing"
+
+func TestGeneratePatchRoundTrip(t *testing.T) {
+	old := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithPlan(PlanStarter).WithEnv("LOG_LEVEL", "info"),
+			NewBackgroundWorker("worker", RuntimeNode),
+			NewPrivateService("legacy", RuntimeNode),
+		).
+		WithDatabases(NewDatabase("main-db"))
+
+	tests := []struct {
+		name   string
+		change func(bp *Blueprint)
+		check  func(t *testing.T, patch *BlueprintPatch)
+	}{
+		{
+			name: "plan change",
+			change: func(bp *Blueprint) {
+				plan := PlanStandard
+				bp.FindService("api").Plan = &plan
+			},
+			check: func(t *testing.T, patch *BlueprintPatch) {
+				if len(patch.SetServices) != 1 || patch.SetServices[0].Name != "api" {
+					t.Errorf("expected only api in the patch, got %+v", patch.SetServices)
+				}
+			},
+		},
+		{
+			name: "added env var",
+			change: func(bp *Blueprint) {
+				worker := bp.FindService("worker")
+				worker.EnvVars = append(worker.EnvVars, EnvVar{Key: stringPtr("QUEUE"), Value: stringPtr("default")})
+			},
+			check: func(t *testing.T, patch *BlueprintPatch) {
+				if len(patch.SetServices) != 1 || patch.SetServices[0].Name != "worker" {
+					t.Errorf("expected only worker in the patch, got %+v", patch.SetServices)
+				}
+			},
+		},
+		{
+			name: "added and removed resources",
+			change: func(bp *Blueprint) {
+				bp.Services = bp.Services[:2]
+				bp.WithDatabases(NewDatabase("analytics-db")).WithPreviews(PreviewGenerationAutomatic, 7)
+			},
+			check: func(t *testing.T, patch *BlueprintPatch) {
+				if len(patch.RemoveServices) != 1 || patch.RemoveServices[0] != "legacy" {
+					t.Errorf("expected legacy to be removed, got %v", patch.RemoveServices)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := CopyBlueprint(old)
+			tt.change(updated)
+
+			patch := GeneratePatch(old, updated)
+			tt.check(t, patch)
+
+			if result := ApplyPatch(old, patch); !result.Equal(updated) {
+				t.Errorf("expected ApplyPatch to reproduce the new blueprint\nexpected: %+v\ngot: %+v", updated, result)
+			}
+		})
+	}
+
+	if patch := GeneratePatch(old, CopyBlueprint(old)); !patch.IsEmpty() {
+		t.Errorf("expected an empty patch for identical blueprints, got %+v", patch)
+	}
+}
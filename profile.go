@@ -0,0 +1,80 @@
+package render
+
+// Profile holds per-environment defaults that are applied to unset blueprint fields
+// Zero values leave the corresponding field untouched
+type Profile struct {
+	// Plan is the default instance plan for services
+	Plan Plan
+	// DatabasePlan is the default plan for databases
+	DatabasePlan Plan
+	// Region is the default region for services and databases
+	Region Region
+	// Previews is the default blueprint-level preview generation
+	Previews PreviewGeneration
+}
+
+// Apply returns a copy of the blueprint with the profile defaults filled in
+// Explicitly-set values always win over the profile
+func (p Profile) Apply(bp *Blueprint) *Blueprint {
+	result := CopyBlueprint(bp)
+
+	for i := range result.Services {
+		fillServiceDefaults(&result.Services[i], p.Plan, p.Region)
+	}
+
+	for i := range result.Databases {
+		db := &result.Databases[i]
+		if db.Plan == nil && p.DatabasePlan != "" {
+			plan := p.DatabasePlan
+			db.Plan = &plan
+		}
+		if db.Region == nil && p.Region != "" {
+			region := p.Region
+			db.Region = &region
+		}
+	}
+
+	if result.Previews == nil && p.Previews != "" {
+		result.Previews = &Previews{Generation: string(p.Previews)}
+	}
+
+	return result
+}
+
+// fillServiceDefaults sets the plan and region of a service when they are unset
+// Empty defaults are ignored
+func fillServiceDefaults(service *Service, plan Plan, region Region) {
+	// Static sites have no plan or region in the Render schema
+	if isStaticSite(*service) {
+		return
+	}
+	if service.Plan == nil && plan != "" {
+		service.Plan = &plan
+	}
+	if service.Region == nil && region != "" {
+		service.Region = &region
+	}
+}
+
+// Defaults are organisation-wide settings remembered by a blueprint from NewBlueprintWithDefaults
+// Zero values leave the corresponding field untouched
+type Defaults struct {
+	// Region is the default region for services added with WithServices
+	Region Region
+	// Plan is the default plan for services added with WithServices
+	Plan Plan
+	// Previews is the blueprint-level preview generation
+	Previews PreviewGeneration
+}
+
+// NewBlueprintWithDefaults creates an empty blueprint that fills unset service plans and
+// regions from d as services are added with WithServices
+// The defaults are not marshaled and are not carried over by CopyBlueprint
+func NewBlueprintWithDefaults(d Defaults) *Blueprint {
+	bp := NewBlueprint()
+	bp.defaults = &d
+	if d.Previews != "" {
+		bp.Previews = &Previews{Generation: string(d.Previews)}
+	}
+	return bp
+}
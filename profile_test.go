@@ -0,0 +1,99 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfileApply(t *testing.T) {
+	prod := Profile{
+		Plan:         PlanStandard,
+		DatabasePlan: PlanPro8GB,
+		Region:       RegionOregon,
+		Previews:     PreviewGenerationNone,
+	}
+
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode),
+			NewBackgroundWorker("worker", RuntimeNode).WithPlan(PlanPro).WithRegion(RegionFrankfurt),
+			NewStaticSite("frontend").WithPublishPath("./dist"),
+		).
+		WithDatabases(
+			NewDatabase("main-db"),
+			NewDatabase("analytics-db").WithPlan(PlanBasic1GB),
+		)
+
+	result := prod.Apply(bp)
+
+	tests := []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{"api plan filled", result.Services[0].Plan, planPtr(PlanStandard)},
+		{"api region filled", result.Services[0].Region, regionPtr(RegionOregon)},
+		{"worker plan kept", result.Services[1].Plan, planPtr(PlanPro)},
+		{"worker region kept", result.Services[1].Region, regionPtr(RegionFrankfurt)},
+		{"static site plan untouched", result.Services[2].Plan, (*Plan)(nil)},
+		{"static site region untouched", result.Services[2].Region, (*Region)(nil)},
+		{"main-db plan filled", result.Databases[0].Plan, planPtr(PlanPro8GB)},
+		{"main-db region filled", result.Databases[0].Region, regionPtr(RegionOregon)},
+		{"analytics-db plan kept", result.Databases[1].Plan, planPtr(PlanBasic1GB)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.actual, tt.expected) {
+				t.Errorf("expected %v, got %v", reflect.Indirect(reflect.ValueOf(tt.expected)), reflect.Indirect(reflect.ValueOf(tt.actual)))
+			}
+		})
+	}
+
+	if result.Previews == nil || result.Previews.Generation != string(PreviewGenerationNone) {
+		t.Errorf("expected previews generation none, got %+v", result.Previews)
+	}
+
+	// The input blueprint is not modified
+	if bp.Services[0].Plan != nil || bp.Databases[0].Region != nil || bp.Previews != nil {
+		t.Error("expected Apply to leave the input blueprint unchanged")
+	}
+
+	// Explicit blueprint previews win
+	bp.WithPreviews(PreviewGenerationAutomatic)
+	if got := prod.Apply(bp).Previews.Generation; got != string(PreviewGenerationAutomatic) {
+		t.Errorf("expected explicit previews to win, got %s", got)
+	}
+}
+
+func TestNewBlueprintWithDefaults(t *testing.T) {
+	bp := NewBlueprintWithDefaults(Defaults{Region: RegionFrankfurt, Plan: PlanStandard, Previews: PreviewGenerationAutomatic})
+
+	bp.WithServices(
+		NewWebService("api", RuntimeNode),
+		NewBackgroundWorker("worker", RuntimeNode).WithRegion(RegionOregon),
+	)
+
+	if got := bp.Services[0].Region; got == nil || *got != RegionFrankfurt {
+		t.Errorf("expected api to inherit region %s, got %v", RegionFrankfurt, got)
+	}
+	if got := bp.Services[0].Plan; got == nil || *got != PlanStandard {
+		t.Errorf("expected api to inherit plan %s, got %v", PlanStandard, got)
+	}
+	if got := *bp.Services[1].Region; got != RegionOregon {
+		t.Errorf("expected the explicit worker region to win, got %s", got)
+	}
+	if bp.Previews == nil || bp.Previews.Generation != string(PreviewGenerationAutomatic) {
+		t.Errorf("expected default previews, got %+v", bp.Previews)
+	}
+
+	// The defaults are not part of the output
+	out, err := bp.ToYAMLString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "defaults") {
+		t.Errorf("expected defaults to stay out of the YAML, got:\n%s", out)
+	}
+}
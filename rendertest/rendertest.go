@@ -0,0 +1,144 @@
+// Package rendertest provides helpers for testing code that builds render blueprints
+package rendertest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	render "github.com/Clause-Logic/render-compose"
+)
+
+// AssertBlueprintsEqual fails the test with a readable diff when two blueprints differ
+func AssertBlueprintsEqual(t testing.TB, want, got *render.Blueprint) {
+	t.Helper()
+
+	if want.Equal(got) {
+		return
+	}
+
+	t.Errorf("blueprints differ:\n  %s", strings.Join(Diff(want, got), "\n  "))
+}
+
+// Diff describes the differences between two blueprints, one line per difference
+// Services, databases and environment groups are matched by name
+func Diff(want, got *render.Blueprint) []string {
+	var diffs []string
+
+	if want == nil || got == nil {
+		if want != got {
+			diffs = append(diffs, fmt.Sprintf("blueprint: want %s, got %s", describeBlueprint(want), describeBlueprint(got)))
+		}
+		return diffs
+	}
+
+	diffs = append(diffs, diffNamed("service", want.Services, got.Services)...)
+	diffs = append(diffs, diffNamed("database", want.Databases, got.Databases)...)
+	diffs = append(diffs, diffNamed("environment group", want.EnvVarGroups, got.EnvVarGroups)...)
+	diffValues("Previews", reflect.ValueOf(want.Previews), reflect.ValueOf(got.Previews), &diffs)
+	diffValues("PreviewsExpireAfterDays", reflect.ValueOf(want.PreviewsExpireAfterDays), reflect.ValueOf(got.PreviewsExpireAfterDays), &diffs)
+
+	return diffs
+}
+
+// diffNamed compares two slices of named resources, matching elements by their Name field
+func diffNamed(kind string, want, got interface{}) []string {
+	var diffs []string
+
+	wantValues := reflect.ValueOf(want)
+	gotValues := reflect.ValueOf(got)
+
+	gotByName := make(map[string]reflect.Value)
+	var gotOrder []string
+	for i := 0; i < gotValues.Len(); i++ {
+		name := gotValues.Index(i).FieldByName("Name").String()
+		gotByName[name] = gotValues.Index(i)
+		gotOrder = append(gotOrder, name)
+	}
+
+	wantNames := make(map[string]bool)
+	var wantOrder []string
+	for i := 0; i < wantValues.Len(); i++ {
+		wantValue := wantValues.Index(i)
+		name := wantValue.FieldByName("Name").String()
+		wantNames[name] = true
+		wantOrder = append(wantOrder, name)
+
+		gotValue, ok := gotByName[name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s %s: missing", kind, name))
+			continue
+		}
+		diffValues(fmt.Sprintf("%s %s", kind, name), wantValue, gotValue, &diffs)
+	}
+
+	for _, name := range gotOrder {
+		if !wantNames[name] {
+			diffs = append(diffs, fmt.Sprintf("%s %s: unexpected", kind, name))
+		}
+	}
+
+	// Report ordering only when the same resources are present
+	if len(diffs) == 0 && !reflect.DeepEqual(wantOrder, gotOrder) {
+		diffs = append(diffs, fmt.Sprintf("%s order: want %v, got %v", kind, wantOrder, gotOrder))
+	}
+
+	return diffs
+}
+
+// diffValues records differences between two values of the same type under the given path
+func diffValues(path string, want, got reflect.Value, diffs *[]string) {
+	switch want.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if want.IsNil() || got.IsNil() {
+			if want.IsNil() != got.IsNil() {
+				*diffs = append(*diffs, fmt.Sprintf("%s: want %s, got %s", path, describe(want), describe(got)))
+			}
+			return
+		}
+		diffValues(path, want.Elem(), got.Elem(), diffs)
+	case reflect.Slice:
+		if want.Len() != got.Len() {
+			*diffs = append(*diffs, fmt.Sprintf("%s: want %d entries, got %d", path, want.Len(), got.Len()))
+			return
+		}
+		for i := 0; i < want.Len(); i++ {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), want.Index(i), got.Index(i), diffs)
+		}
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			field := want.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			diffValues(path+"."+field.Name, want.Field(i), got.Field(i), diffs)
+		}
+	default:
+		if want.Interface() != got.Interface() {
+			*diffs = append(*diffs, fmt.Sprintf("%s: want %s, got %s", path, describe(want), describe(got)))
+		}
+	}
+}
+
+// describe formats a value for a diff line
+func describe(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "nil"
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprintf("%+v", v.Interface())
+}
+
+// describeBlueprint formats a possibly-nil blueprint for a diff line
+func describeBlueprint(bp *render.Blueprint) string {
+	if bp == nil {
+		return "nil"
+	}
+	return "non-nil"
+}
@@ -0,0 +1,88 @@
+package rendertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	render "github.com/Clause-Logic/render-compose"
+)
+
+// recorder captures failures instead of failing the enclosing test
+type recorder struct {
+	testing.TB
+	messages []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+func newBlueprint(plan render.Plan) *render.Blueprint {
+	api := render.NewWebService("api", render.RuntimeNode).
+		WithPlan(plan).
+		WithEnv("NODE_ENV", "production")
+	worker := render.NewBackgroundWorker("worker", render.RuntimePython)
+	return render.NewBlueprint().
+		WithServices(api, worker).
+		WithDatabases(render.NewDatabase("main-db"))
+}
+
+func TestAssertBlueprintsEqual(t *testing.T) {
+	rec := &recorder{TB: t}
+	AssertBlueprintsEqual(rec, newBlueprint(render.PlanStarter), newBlueprint(render.PlanStarter))
+	if len(rec.messages) != 0 {
+		t.Errorf("expected no failures for equal blueprints, got %v", rec.messages)
+	}
+
+	// Zero-value and constructed empty blueprints are equal
+	rec = &recorder{TB: t}
+	AssertBlueprintsEqual(rec, render.NewBlueprint(), &render.Blueprint{})
+	if len(rec.messages) != 0 {
+		t.Errorf("expected no failures for empty blueprints, got %v", rec.messages)
+	}
+}
+
+func TestAssertBlueprintsEqualReportsDiff(t *testing.T) {
+	rec := &recorder{TB: t}
+	got := newBlueprint(render.PlanStandard)
+	got.Databases = nil
+
+	AssertBlueprintsEqual(rec, newBlueprint(render.PlanStarter), got)
+
+	if len(rec.messages) != 1 {
+		t.Fatalf("expected one failure, got %v", rec.messages)
+	}
+	message := rec.messages[0]
+	for _, expected := range []string{
+		`service api.Plan: want "starter", got "standard"`,
+		"database main-db: missing",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("expected failure to mention %q, got:\n%s", expected, message)
+		}
+	}
+	if strings.Contains(message, "service worker") {
+		t.Errorf("unchanged service should not be reported, got:\n%s", message)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	want := newBlueprint(render.PlanStarter)
+	got := newBlueprint(render.PlanStarter)
+	got.Services[0], got.Services[1] = got.Services[1], got.Services[0]
+
+	diffs := Diff(want, got)
+	if len(diffs) != 1 || !strings.HasPrefix(diffs[0], "service order") {
+		t.Errorf("expected only an ordering difference, got %v", diffs)
+	}
+
+	if diffs := Diff(nil, nil); len(diffs) != 0 {
+		t.Errorf("expected no differences between nil blueprints, got %v", diffs)
+	}
+	if diffs := Diff(nil, want); len(diffs) != 1 {
+		t.Errorf("expected a difference between nil and non-nil, got %v", diffs)
+	}
+}
@@ -1,5 +1,7 @@
 package render
 
+import "fmt"
+
 // Database builder functions
 
 // NewDatabase creates a new Database with the given name
@@ -15,6 +17,26 @@ func (db *Database) WithPlan(plan Plan) *Database {
 	return db
 }
 
+// WithPlanString sets the database plan from a string such as a CLI flag
+// An unknown plan or a service plan is recorded in Errors and leaves the plan unchanged
+func (db *Database) WithPlanString(plan string) *Database {
+	parsed, err := ParsePlan(plan)
+	if err != nil {
+		db.errs = append(db.errs, err)
+		return db
+	}
+	if !parsed.IsDatabasePlan() {
+		db.errs = append(db.errs, fmt.Errorf("plan %q is not a database plan", plan))
+		return db
+	}
+	return db.WithPlan(parsed)
+}
+
+// Errors returns the problems recorded while building the database
+func (db *Database) Errors() []error {
+	return db.errs
+}
+
 // WithPreviewPlan sets the preview environment plan
 func (db *Database) WithPreviewPlan(plan Plan) *Database {
 	db.PreviewPlan = &plan
@@ -91,6 +113,13 @@ func (db *Database) WithReadReplicas(names ...string) *Database {
 	return db
 }
 
+// WithReadReplicaInRegion adds a read replica with a region used only by validation
+// The region is not written to render.yaml, since Render does not accept one for replicas
+func (db *Database) WithReadReplicaInRegion(name string, region Region) *Database {
+	db.ReadReplicas = append(db.ReadReplicas, ReadReplica{Name: name, Region: &region})
+	return db
+}
+
 // WithHighAvailability enables high availability
 func (db *Database) WithHighAvailability() *Database {
 	db.HighAvailability = &HighAvailability{Enabled: true}
@@ -113,6 +142,27 @@ func (evg *EnvVarGroup) WithEnvVars(envVars ...EnvVar) *EnvVarGroup {
 	return evg
 }
 
+// WithEnvVarsDedup adds environment variables to the group, replacing any existing
+// variable with the same key in place instead of appending a duplicate
+func (evg *EnvVarGroup) WithEnvVarsDedup(envVars ...EnvVar) *EnvVarGroup {
+	for _, envVar := range envVars {
+		replaced := false
+		if envVar.Key != nil {
+			for i, existing := range evg.EnvVars {
+				if existing.Key != nil && *existing.Key == *envVar.Key {
+					evg.EnvVars[i] = envVar
+					replaced = true
+					break
+				}
+			}
+		}
+		if !replaced {
+			evg.EnvVars = append(evg.EnvVars, envVar)
+		}
+	}
+	return evg
+}
+
 // WithEnv adds a simple key-value environment variable
 func (evg *EnvVarGroup) WithEnv(key, value string) *EnvVarGroup {
 	return evg.WithEnvVars(Env(key, value))
@@ -140,9 +190,14 @@ func NewBlueprint() *Blueprint {
 }
 
 // WithServices adds services to the blueprint
+// Blueprints from NewBlueprintWithDefaults fill unset plans and regions from their defaults
 func (bp *Blueprint) WithServices(services ...ServiceBuilder) *Blueprint {
 	for _, svc := range services {
-		bp.Services = append(bp.Services, *svc.ToService())
+		service := *svc.ToService()
+		if bp.defaults != nil {
+			fillServiceDefaults(&service, bp.defaults.Plan, bp.defaults.Region)
+		}
+		bp.Services = append(bp.Services, service)
 	}
 	return bp
 }
@@ -172,6 +227,20 @@ func (bp *Blueprint) WithPreviews(generation PreviewGeneration, expireAfterDays
 	return bp
 }
 
+// WithDefaultAutoDeploy sets autoDeploy on every service that does not set it yet
+// Key-value services have no deploys and are left alone
+func (bp *Blueprint) WithDefaultAutoDeploy(enabled bool) *Blueprint {
+	for i := range bp.Services {
+		service := &bp.Services[i]
+		if service.AutoDeploy != nil || service.Type == ServiceTypeKeyValue || service.Type == ServiceTypeRedis {
+			continue
+		}
+		autoDeploy := enabled
+		service.AutoDeploy = &autoDeploy
+	}
+	return bp
+}
+
 // Helper function for environment variable references
 
 // EnvFromGroup creates an environment variable reference to a group
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion selects one of the render.yaml JSON schemas pinned in the library
+type SchemaVersion string
+
+// Schema Versions
+const (
+	// SchemaV1 is the schema from before Key Value, when key-value stores were typed redis
+	SchemaV1 SchemaVersion = "v1"
+	// SchemaV2 adds the keyvalue service type and keeps redis as a deprecated alias
+	SchemaV2 SchemaVersion = "v2"
+)
+
+// RenderSchemaURL is where Render publishes the latest render.yaml JSON schema
+const RenderSchemaURL = "https://render.com/schema/render.yaml.json"
+
+//go:embed schemas/*.json
+var pinnedSchemas embed.FS
+
+// ValidateAgainstSchemaVersion checks the YAML form of the blueprint against a pinned schema
+// It returns one message per schema violation; the error reports an unknown version or a
+// blueprint that could not be marshaled
+func ValidateAgainstSchemaVersion(bp *Blueprint, version SchemaVersion) ([]string, error) {
+	schema, err := pinnedSchemas.ReadFile("schemas/render-" + string(version) + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unsupported schema version %q", version)
+	}
+	return validateAgainstSchema(bp, gojsonschema.NewBytesLoader(schema))
+}
+
+// ValidateAgainstSchemaURL checks the YAML form of the blueprint against the schema at url,
+// such as RenderSchemaURL for the latest published schema or a file:// URL
+func ValidateAgainstSchemaURL(bp *Blueprint, url string) ([]string, error) {
+	return validateAgainstSchema(bp, gojsonschema.NewReferenceLoader(url))
+}
+
+// validateAgainstSchema renders the blueprint to YAML, converts it to JSON and validates it
+func validateAgainstSchema(bp *Blueprint, schemaLoader gojsonschema.JSONLoader) ([]string, error) {
+	yamlStr, err := bp.ToYAMLString()
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal([]byte(yamlStr), &document); err != nil {
+		return nil, fmt.Errorf("failed to parse generated YAML: %w", err)
+	}
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert blueprint to JSON: %w", err)
+	}
+
+	result, err := gojsonschema.Validate(schemaLoader, gojsonschema.NewBytesLoader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate against schema: %w", err)
+	}
+
+	var messages []string
+	for _, desc := range result.Errors() {
+		messages = append(messages, desc.String())
+	}
+	return messages, nil
+}
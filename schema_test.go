@@ -2,25 +2,17 @@ package render
 
 import (
 	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
 )
 
-// Test that validates our generated YAML against Render's official JSON schema
+// Test that validates our generated YAML against the pinned Render JSON schema
 func TestYAMLAgainstRenderSchema(t *testing.T) {
-	// Fetch the official schema
-	schema, err := fetchRenderSchema()
-	if err != nil {
-		t.Skipf("Could not fetch Render schema: %v", err)
-	}
-
-	schemaLoader := gojsonschema.NewStringLoader(schema)
-
 	tests := []struct {
 		name      string
 		blueprint func() *Blueprint
@@ -184,42 +176,40 @@ func TestYAMLAgainstRenderSchema(t *testing.T) {
 				return NewBlueprint().WithServices(pserv)
 			},
 		},
+		{
+			name: "Normalized Redis Service",
+			blueprint: func() *Blueprint {
+				var bp Blueprint
+				if err := yaml.Unmarshal([]byte(`services:
+  - name: cache
+    type: redis
+    plan: starter
+    ipAllowList: []
+    maxmemoryPolicy: noeviction
+`), &bp); err != nil {
+					t.Fatalf("Failed to parse YAML: %v", err)
+				}
+				bp.NormalizeKeyValueTypes()
+				return &bp
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			blueprint := tt.blueprint()
 
-			// Convert blueprint to YAML string
-			yamlStr, err := blueprint.ToYAMLString()
-			if err != nil {
-				t.Fatalf("Failed to convert blueprint to YAML: %v", err)
-			}
-
-			// Convert YAML to JSON for schema validation
-			var yamlData interface{}
-			if err := yaml.Unmarshal([]byte(yamlStr), &yamlData); err != nil {
-				t.Fatalf("Failed to parse YAML: %v", err)
-			}
-
-			jsonData, err := json.Marshal(yamlData)
-			if err != nil {
-				t.Fatalf("Failed to convert to JSON: %v", err)
-			}
-
-			documentLoader := gojsonschema.NewBytesLoader(jsonData)
-
-			// Validate against schema
-			result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+			violations, err := ValidateAgainstSchemaVersion(blueprint, SchemaV2)
 			if err != nil {
 				t.Fatalf("Schema validation failed: %v", err)
 			}
 
-			if !result.Valid() {
+			if len(violations) > 0 {
 				t.Errorf("Generated YAML does not match Render schema:")
-				for _, desc := range result.Errors() {
-					t.Errorf("- %s", desc)
+				for _, violation := range violations {
+					t.Errorf("- %s", violation)
 				}
+				yamlStr, _ := blueprint.ToYAMLString()
 				t.Logf("Generated YAML:\n%s", yamlStr)
 			}
 		})
@@ -228,13 +218,6 @@ func TestYAMLAgainstRenderSchema(t *testing.T) {
 
 // Test validation of invalid configurations
 func TestInvalidConfigurationsAgainstSchema(t *testing.T) {
-	schema, err := fetchRenderSchema()
-	if err != nil {
-		t.Skipf("Could not fetch Render schema: %v", err)
-	}
-
-	schemaLoader := gojsonschema.NewStringLoader(schema)
-
 	tests := []struct {
 		name      string
 		blueprint func() *Blueprint
@@ -279,39 +262,24 @@ func TestInvalidConfigurationsAgainstSchema(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			blueprint := tt.blueprint()
 
-			yamlStr, err := blueprint.ToYAMLString()
-			if err != nil && tt.expectErr {
-				return // Expected to fail at YAML generation
-			}
-
-			var yamlData interface{}
-			if err := yaml.Unmarshal([]byte(yamlStr), &yamlData); err != nil {
+			violations, err := ValidateAgainstSchemaVersion(blueprint, SchemaV2)
+			if err != nil {
 				if tt.expectErr {
-					return // Expected to fail
+					return // Expected to fail at YAML generation
 				}
-				t.Fatalf("Failed to parse YAML: %v", err)
-			}
-
-			jsonData, err := json.Marshal(yamlData)
-			if err != nil {
-				t.Fatalf("Failed to convert to JSON: %v", err)
-			}
-
-			documentLoader := gojsonschema.NewBytesLoader(jsonData)
-			result, err := gojsonschema.Validate(schemaLoader, documentLoader)
-			if err != nil {
 				t.Fatalf("Schema validation failed: %v", err)
 			}
 
-			if tt.expectErr && result.Valid() {
+			yamlStr, _ := blueprint.ToYAMLString()
+			if tt.expectErr && len(violations) == 0 {
 				t.Errorf("Expected validation to fail, but it passed")
 				t.Logf("Generated YAML:\n%s", yamlStr)
 			}
 
-			if !tt.expectErr && !result.Valid() {
+			if !tt.expectErr && len(violations) > 0 {
 				t.Errorf("Unexpected validation failure:")
-				for _, desc := range result.Errors() {
-					t.Errorf("- %s", desc)
+				for _, violation := range violations {
+					t.Errorf("- %s", violation)
 				}
 				t.Logf("Generated YAML:\n%s", yamlStr)
 			}
@@ -319,34 +287,91 @@ func TestInvalidConfigurationsAgainstSchema(t *testing.T) {
 	}
 }
 
-// Fetch the official Render schema
-func fetchRenderSchema() (string, error) {
-	resp, err := http.Get("https://render.com/schema/render.yaml.json")
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch schema: %w", err)
+func TestValidateAgainstSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		blueprint  *Blueprint
+		validForV1 bool
+		validForV2 bool
+	}{
+		{
+			name: "web service with database",
+			blueprint: NewBlueprint().
+				WithServices(NewWebService("api", RuntimeNode).WithPlan(PlanStarter).WithStartCommand("npm start")).
+				WithDatabases(NewDatabase("main-db").WithPlan(PlanBasic1GB)),
+			validForV1: true,
+			validForV2: true,
+		},
+		{
+			name:       "key value service",
+			blueprint:  NewBlueprint().WithServices(NewKeyValueService("cache").WithPublicAccess()),
+			validForV1: false,
+			validForV2: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for version, valid := range map[SchemaVersion]bool{SchemaV1: tt.validForV1, SchemaV2: tt.validForV2} {
+				violations, err := ValidateAgainstSchemaVersion(tt.blueprint, version)
+				if err != nil {
+					t.Fatalf("unexpected error for %s: %v", version, err)
+				}
+				if valid != (len(violations) == 0) {
+					t.Errorf("expected valid=%v against %s, got violations %v", valid, version, violations)
+				}
+			}
+		})
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch schema: HTTP %d", resp.StatusCode)
+	if _, err := ValidateAgainstSchemaVersion(NewBlueprint(), SchemaVersion("v0")); err == nil || !strings.Contains(err.Error(), "unsupported schema version") {
+		t.Errorf("expected an unsupported version error, got %v", err)
 	}
+}
 
-	body, err := io.ReadAll(resp.Body)
+func TestValidateAgainstSchemaURL(t *testing.T) {
+	schema, err := pinnedSchemas.ReadFile("schemas/render-v2.json")
 	if err != nil {
-		return "", fmt.Errorf("failed to read schema: %w", err)
+		t.Fatalf("failed to read pinned schema: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "render.yaml.json")
+	if err := os.WriteFile(path, schema, 0644); err != nil {
+		t.Fatalf("failed to write schema: %v", err)
 	}
 
-	return string(body), nil
+	bp := NewBlueprint().WithServices(NewKeyValueService("cache").WithPublicAccess())
+	violations, err := ValidateAgainstSchemaURL(bp, "file://"+filepath.ToSlash(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+}
+
+// Test that the pinned schemas have not drifted from the latest published one for common blueprints
+func TestValidateAgainstLatestRenderSchema(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).WithPlan(PlanStarter).WithStartCommand("npm start")).
+		WithDatabases(NewDatabase("main-db").WithPlan(PlanBasic1GB))
+
+	violations, err := ValidateAgainstSchemaURL(bp, RenderSchemaURL)
+	if err != nil {
+		t.Skipf("Could not fetch Render schema: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("expected no violations against the latest schema, got %v", violations)
+	}
 }
 
 // Benchmark schema validation performance
 func BenchmarkSchemaValidation(b *testing.B) {
-	schema, err := fetchRenderSchema()
+	schema, err := pinnedSchemas.ReadFile("schemas/render-v2.json")
 	if err != nil {
-		b.Skipf("Could not fetch Render schema: %v", err)
+		b.Fatalf("Failed to read pinned schema: %v", err)
 	}
 
-	schemaLoader := gojsonschema.NewStringLoader(schema)
+	schemaLoader := gojsonschema.NewBytesLoader(schema)
 
 	// Create a complex blueprint for benchmarking
 	api := NewWebService("api", RuntimeNode).
@@ -1,5 +1,11 @@
 package render
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 // Configuration abstractions for related fields
 
 // DockerConfig groups Docker-related configuration
@@ -75,6 +81,12 @@ type WebService struct {
 	EnvVars                 []EnvVar       `yaml:"envVars,omitempty"`
 	MaxShutdownDelaySeconds *int           `yaml:"maxShutdownDelaySeconds,omitempty"`
 	Disk                    *Disk          `yaml:"disk,omitempty"`
+
+	// Tags select services with SelectByTag and are never written to YAML
+	Tags []string `yaml:"-"`
+
+	// errs holds problems recorded while building, see Errors
+	errs []error
 }
 
 // ToService converts WebService to generic Service
@@ -91,6 +103,7 @@ func (ws *WebService) ToService() *Service {
 		MaxShutdownDelaySeconds: ws.MaxShutdownDelaySeconds,
 		Disk:                    ws.Disk,
 		HealthCheckPath:         ws.HealthCheckPath,
+		Tags:                    ws.Tags,
 	}
 
 	// Apply Git configuration
@@ -151,6 +164,12 @@ type BackgroundWorker struct {
 	EnvVars                 []EnvVar       `yaml:"envVars,omitempty"`
 	MaxShutdownDelaySeconds *int           `yaml:"maxShutdownDelaySeconds,omitempty"`
 	Disk                    *Disk          `yaml:"disk,omitempty"`
+
+	// Tags select services with SelectByTag and are never written to YAML
+	Tags []string `yaml:"-"`
+
+	// errs holds problems recorded while building, see Errors
+	errs []error
 }
 
 // ToService converts BackgroundWorker to generic Service
@@ -165,6 +184,7 @@ func (bw *BackgroundWorker) ToService() *Service {
 		EnvVars:                 bw.EnvVars,
 		MaxShutdownDelaySeconds: bw.MaxShutdownDelaySeconds,
 		Disk:                    bw.Disk,
+		Tags:                    bw.Tags,
 	}
 
 	// Apply Git configuration
@@ -219,6 +239,12 @@ type PrivateService struct {
 	EnvVars                 []EnvVar       `yaml:"envVars,omitempty"`
 	MaxShutdownDelaySeconds *int           `yaml:"maxShutdownDelaySeconds,omitempty"`
 	Disk                    *Disk          `yaml:"disk,omitempty"`
+
+	// Tags select services with SelectByTag and are never written to YAML
+	Tags []string `yaml:"-"`
+
+	// errs holds problems recorded while building, see Errors
+	errs []error
 }
 
 // ToService converts PrivateService to generic Service
@@ -233,6 +259,7 @@ func (ps *PrivateService) ToService() *Service {
 		EnvVars:                 ps.EnvVars,
 		MaxShutdownDelaySeconds: ps.MaxShutdownDelaySeconds,
 		Disk:                    ps.Disk,
+		Tags:                    ps.Tags,
 	}
 
 	// Apply Git configuration
@@ -285,6 +312,12 @@ type CronJob struct {
 	Docker  *DockerConfig  `yaml:",inline,omitempty"`
 	Preview *PreviewConfig `yaml:",inline,omitempty"`
 	EnvVars []EnvVar       `yaml:"envVars,omitempty"`
+
+	// Tags select services with SelectByTag and are never written to YAML
+	Tags []string `yaml:"-"`
+
+	// errs holds problems recorded while building, see Errors
+	errs []error
 }
 
 // ToService converts CronJob to generic Service
@@ -297,6 +330,7 @@ func (cj *CronJob) ToService() *Service {
 		Region:       cj.Region,
 		EnvVars:      cj.EnvVars,
 		Schedule:     &cj.Schedule,
+		Tags:         cj.Tags,
 	}
 
 	// Apply Git configuration
@@ -346,6 +380,9 @@ type StaticSite struct {
 	StaticSite *StaticSiteConfig `yaml:",inline"`
 	Preview    *PreviewConfig    `yaml:",inline,omitempty"`
 	Domains    []string          `yaml:"domains,omitempty"`
+
+	// Tags select services with SelectByTag and are never written to YAML
+	Tags []string `yaml:"-"`
 }
 
 // ToService converts StaticSite to generic Service
@@ -357,6 +394,7 @@ func (ss *StaticSite) ToService() *Service {
 		Runtime: &runtime,
 		Domains: ss.Domains,
 		Region:  ss.Region,
+		Tags:    ss.Tags,
 	}
 
 	// Apply Git configuration
@@ -474,6 +512,12 @@ type KeyValueService struct {
 	// Configuration groups
 	KeyValue *KeyValueConfig `yaml:",inline"`
 	Preview  *PreviewConfig  `yaml:",inline,omitempty"`
+
+	// Tags select services with SelectByTag and are never written to YAML
+	Tags []string `yaml:"-"`
+
+	// errs holds problems recorded while building, see Errors
+	errs []error
 }
 
 // ToService converts KeyValueService to generic Service
@@ -483,6 +527,7 @@ func (kvs *KeyValueService) ToService() *Service {
 		Type:   ServiceTypeKeyValue,
 		Plan:   kvs.Plan,
 		Region: kvs.Region,
+		Tags:   kvs.Tags,
 	}
 
 	// Apply KeyValue configuration
@@ -505,6 +550,61 @@ type ServiceBuilder interface {
 	ToService() *Service
 }
 
+// envVarAppender is implemented by builders that carry environment variables
+type envVarAppender interface {
+	appendEnvVars(envVars ...EnvVar)
+}
+
+func (ws *WebService) appendEnvVars(envVars ...EnvVar) {
+	ws.EnvVars = append(ws.EnvVars, envVars...)
+}
+
+func (bw *BackgroundWorker) appendEnvVars(envVars ...EnvVar) {
+	bw.EnvVars = append(bw.EnvVars, envVars...)
+}
+
+func (ps *PrivateService) appendEnvVars(envVars ...EnvVar) {
+	ps.EnvVars = append(ps.EnvVars, envVars...)
+}
+
+func (cj *CronJob) appendEnvVars(envVars ...EnvVar) {
+	cj.EnvVars = append(cj.EnvVars, envVars...)
+}
+
+// RegionSetter is implemented by builders whose region can be set generically
+type RegionSetter interface {
+	SetRegion(region Region)
+}
+
+// SetRegion sets the deployment region
+func (ws *WebService) SetRegion(region Region) { ws.WithRegion(region) }
+
+// SetRegion sets the deployment region
+func (bw *BackgroundWorker) SetRegion(region Region) { bw.WithRegion(region) }
+
+// SetRegion sets the deployment region
+func (ps *PrivateService) SetRegion(region Region) { ps.WithRegion(region) }
+
+// SetRegion sets the deployment region
+func (cj *CronJob) SetRegion(region Region) { cj.WithRegion(region) }
+
+// SetRegion sets the deployment region
+func (ss *StaticSite) SetRegion(region Region) { ss.WithRegion(region) }
+
+// SetRegion sets the deployment region
+func (kvs *KeyValueService) SetRegion(region Region) { kvs.WithRegion(region) }
+
+// InRegion sets the same region on every builder and returns them for WithServices
+// Builders that do not implement RegionSetter are returned unchanged
+func InRegion(region Region, builders ...ServiceBuilder) []ServiceBuilder {
+	for _, builder := range builders {
+		if setter, ok := builder.(RegionSetter); ok {
+			setter.SetRegion(region)
+		}
+	}
+	return builders
+}
+
 // Convenience function to build a Blueprint from specific service types
 func NewBlueprintFromServices(services []ServiceBuilder, databases []Database, envGroups []EnvVarGroup) *Blueprint {
 	genericServices := make([]Service, len(services))
@@ -541,24 +641,61 @@ func (ws *WebService) WithHealthCheck(path string) *WebService {
 	return ws
 }
 
+// WithHealthCheckPath is an alias for WithHealthCheck
+func (ws *WebService) WithHealthCheckPath(path string) *WebService {
+	return ws.WithHealthCheck(path)
+}
+
+// WithoutHealthCheck clears the health check path, e.g. one set by a base template
+func (ws *WebService) WithoutHealthCheck() *WebService {
+	ws.HealthCheckPath = nil
+	return ws
+}
+
 // WithStartCommand sets the start command
 func (ws *WebService) WithStartCommand(cmd string) *WebService {
 	ws.StartCommand = &cmd
 	return ws
 }
 
+// WithStartCommandArgs sets the start command from separate arguments, shell-quoting each one
+func (ws *WebService) WithStartCommandArgs(args ...string) *WebService {
+	return ws.WithStartCommand(ShellJoin(args...))
+}
+
 // WithPlan sets the instance plan
 func (ws *WebService) WithPlan(plan Plan) *WebService {
 	ws.Plan = &plan
 	return ws
 }
 
+// WithPlanString sets the plan from a string such as a CLI flag
+// An unknown plan or a database plan is recorded in Errors and leaves the plan unchanged
+func (ws *WebService) WithPlanString(plan string) *WebService {
+	parsed, err := ParsePlan(plan)
+	if err != nil {
+		ws.errs = append(ws.errs, err)
+		return ws
+	}
+	if !parsed.IsServicePlan() {
+		ws.errs = append(ws.errs, fmt.Errorf("plan %q is not a service plan", plan))
+		return ws
+	}
+	return ws.WithPlan(parsed)
+}
+
 // WithRegion sets the region
 func (ws *WebService) WithRegion(region Region) *WebService {
 	ws.Region = &region
 	return ws
 }
 
+// WithTags adds tags used to select services with SelectByTag
+func (ws *WebService) WithTags(tags ...string) *WebService {
+	ws.Tags = append(ws.Tags, tags...)
+	return ws
+}
+
 // WithGit configures Git repository settings
 func (ws *WebService) WithGit(repo string, branch ...string) *WebService {
 	ws.Git = &GitConfig{Repo: &repo}
@@ -595,6 +732,15 @@ func (ws *WebService) WithAutoDeploy(enabled bool) *WebService {
 	return ws
 }
 
+// WithPreviewEnvironment configures preview generation and the preview instance plan
+func (ws *WebService) WithPreviewEnvironment(generation PreviewGeneration, plan Plan) *WebService {
+	ws.Preview = &PreviewConfig{
+		Previews:    &ServicePreviews{Generation: string(generation)},
+		PreviewPlan: &plan,
+	}
+	return ws
+}
+
 // WithDocker configures Docker settings
 func (ws *WebService) WithDocker(config *DockerConfig) *WebService {
 	ws.Docker = config
@@ -622,6 +768,15 @@ func (ws *WebService) WithDockerImage(imageURL string) *WebService {
 	return ws
 }
 
+// WithDockerImageDigest sets a prebuilt Docker image pinned to a sha256 digest
+// so every deploy pulls the same image
+func (ws *WebService) WithDockerImageDigest(repo, digest string) *WebService {
+	if !imageDigestPattern.MatchString(digest) {
+		ws.errs = append(ws.errs, fmt.Errorf("image digest %q is not of the form sha256:<64 hex characters>", digest))
+	}
+	return ws.WithDockerImage(repo + "@" + digest)
+}
+
 // WithScaling configures manual scaling
 func (ws *WebService) WithScaling(numInstances int) *WebService {
 	if ws.Scaling == nil {
@@ -647,6 +802,32 @@ func (ws *WebService) WithAutoScaling(min, max int, targetCPU ...int) *WebServic
 	return ws
 }
 
+// WithScalingSpec attaches a prebuilt autoscaling configuration as-is
+// Inverted bounds are recorded, see Errors
+func (ws *WebService) WithScalingSpec(scaling *Scaling) *WebService {
+	if scaling != nil && scaling.MinInstances != nil && scaling.MaxInstances != nil && *scaling.MinInstances > *scaling.MaxInstances {
+		ws.errs = append(ws.errs, fmt.Errorf("scaling minInstances %d exceeds maxInstances %d", *scaling.MinInstances, *scaling.MaxInstances))
+	}
+	if ws.Scaling == nil {
+		ws.Scaling = &ScalingConfig{}
+	}
+	ws.Scaling.Scaling = scaling
+	return ws
+}
+
+// WithAutoScalingTargets enables autoscaling with CPU and memory targets
+// A target of zero leaves that trigger unset
+func (ws *WebService) WithAutoScalingTargets(min, max, targetCPU, targetMemory int) *WebService {
+	ws.WithAutoScaling(min, max)
+	if targetCPU > 0 {
+		ws.Scaling.Scaling.TargetCPUPercent = &targetCPU
+	}
+	if targetMemory > 0 {
+		ws.Scaling.Scaling.TargetMemoryPercent = &targetMemory
+	}
+	return ws
+}
+
 // WithEnvVars adds environment variables
 func (ws *WebService) WithEnvVars(envVars ...EnvVar) *WebService {
 	ws.EnvVars = append(ws.EnvVars, envVars...)
@@ -662,19 +843,64 @@ func (ws *WebService) WithEnv(key, value string) *WebService {
 	return ws
 }
 
+// WithDatabaseURL adds DATABASE_URL from the named database's connection string
+func (ws *WebService) WithDatabaseURL(dbName string) *WebService {
+	ws.EnvVars = append(ws.EnvVars, EnvDatabaseURL(dbName))
+	return ws
+}
+
+// WithDatabaseProperties adds one env var per database property, see EnvsFromDatabaseProperties
+func (ws *WebService) WithDatabaseProperties(prefix, dbName string, props ...DatabaseProperty) *WebService {
+	ws.EnvVars = append(ws.EnvVars, EnvsFromDatabaseProperties(prefix, dbName, props...)...)
+	return ws
+}
+
+// WithEnvFromGroupsOrdered includes environment variable groups in the given order
+// When groups define the same key, later groups take precedence over earlier ones
+func (ws *WebService) WithEnvFromGroupsOrdered(names ...string) *WebService {
+	for _, name := range names {
+		ws.EnvVars = append(ws.EnvVars, EnvFromGroup(name))
+	}
+	return ws
+}
+
+// WithEnvFileOrPanic adds the entries of a .env file, panicking if it cannot be loaded
+// Use LoadEnvInto to handle the error instead
+func (ws *WebService) WithEnvFileOrPanic(path string) *WebService {
+	if err := LoadEnvInto(ws, path); err != nil {
+		panic(err)
+	}
+	return ws
+}
+
 // WithDisk configures persistent disk
+// An obviously oversized disk is recorded in Errors rather than interrupting the chain
 func (ws *WebService) WithDisk(name, mountPath string, sizeGB ...int) *WebService {
-	disk := &Disk{
+	disk := Disk{
 		Name:      name,
 		MountPath: mountPath,
 	}
 	if len(sizeGB) > 0 {
 		disk.SizeGB = &sizeGB[0]
 	}
-	ws.Disk = disk
+	return ws.WithDiskSpec(disk)
+}
+
+// WithDiskSpec configures persistent disk from a complete Disk value
+// An obviously oversized disk is recorded in Errors rather than interrupting the chain
+func (ws *WebService) WithDiskSpec(disk Disk) *WebService {
+	if disk.SizeGB != nil && *disk.SizeGB > MaxDiskSizeGB {
+		ws.errs = append(ws.errs, fmt.Errorf("disk %s size %dGB exceeds the %dGB maximum", disk.Name, *disk.SizeGB, MaxDiskSizeGB))
+	}
+	ws.Disk = &disk
 	return ws
 }
 
+// Errors returns the problems recorded while building the service
+func (ws *WebService) Errors() []error {
+	return ws.errs
+}
+
 // NewBackgroundWorker creates a new BackgroundWorker
 func NewBackgroundWorker(name string, runtime Runtime) *BackgroundWorker {
 	return &BackgroundWorker{
@@ -697,18 +923,49 @@ func (bw *BackgroundWorker) WithStartCommand(cmd string) *BackgroundWorker {
 	return bw
 }
 
+// WithStartCommandArgs sets the start command from separate arguments, shell-quoting each one
+func (bw *BackgroundWorker) WithStartCommandArgs(args ...string) *BackgroundWorker {
+	return bw.WithStartCommand(ShellJoin(args...))
+}
+
 // WithPlan sets the instance plan for the worker
 func (bw *BackgroundWorker) WithPlan(plan Plan) *BackgroundWorker {
 	bw.Plan = &plan
 	return bw
 }
 
+// WithPlanString sets the plan from a string such as a CLI flag
+// An unknown plan or a database plan is recorded in Errors and leaves the plan unchanged
+func (bw *BackgroundWorker) WithPlanString(plan string) *BackgroundWorker {
+	parsed, err := ParsePlan(plan)
+	if err != nil {
+		bw.errs = append(bw.errs, err)
+		return bw
+	}
+	if !parsed.IsServicePlan() {
+		bw.errs = append(bw.errs, fmt.Errorf("plan %q is not a service plan", plan))
+		return bw
+	}
+	return bw.WithPlan(parsed)
+}
+
+// Errors returns the problems recorded while building the service
+func (bw *BackgroundWorker) Errors() []error {
+	return bw.errs
+}
+
 // WithRegion sets the region for the worker
 func (bw *BackgroundWorker) WithRegion(region Region) *BackgroundWorker {
 	bw.Region = &region
 	return bw
 }
 
+// WithTags adds tags used to select services with SelectByTag
+func (bw *BackgroundWorker) WithTags(tags ...string) *BackgroundWorker {
+	bw.Tags = append(bw.Tags, tags...)
+	return bw
+}
+
 // WithGit configures Git repository settings for the worker
 func (bw *BackgroundWorker) WithGit(repo string, branch ...string) *BackgroundWorker {
 	bw.Git = &GitConfig{Repo: &repo}
@@ -727,6 +984,15 @@ func (bw *BackgroundWorker) WithBuild(buildCmd string) *BackgroundWorker {
 	return bw
 }
 
+// WithPreviewEnvironment configures preview generation and the preview instance plan
+func (bw *BackgroundWorker) WithPreviewEnvironment(generation PreviewGeneration, plan Plan) *BackgroundWorker {
+	bw.Preview = &PreviewConfig{
+		Previews:    &ServicePreviews{Generation: string(generation)},
+		PreviewPlan: &plan,
+	}
+	return bw
+}
+
 // WithEnvVars adds environment variables to the worker
 func (bw *BackgroundWorker) WithEnvVars(envVars ...EnvVar) *BackgroundWorker {
 	bw.EnvVars = append(bw.EnvVars, envVars...)
@@ -742,24 +1008,85 @@ func (bw *BackgroundWorker) WithEnv(key, value string) *BackgroundWorker {
 	return bw
 }
 
+// WithDatabaseURL adds DATABASE_URL from the named database's connection string
+func (bw *BackgroundWorker) WithDatabaseURL(dbName string) *BackgroundWorker {
+	bw.EnvVars = append(bw.EnvVars, EnvDatabaseURL(dbName))
+	return bw
+}
+
+// WithDatabaseProperties adds one env var per database property, see EnvsFromDatabaseProperties
+func (bw *BackgroundWorker) WithDatabaseProperties(prefix, dbName string, props ...DatabaseProperty) *BackgroundWorker {
+	bw.EnvVars = append(bw.EnvVars, EnvsFromDatabaseProperties(prefix, dbName, props...)...)
+	return bw
+}
+
+// WithEnvFromGroupsOrdered includes environment variable groups in the given order
+// When groups define the same key, later groups take precedence over earlier ones
+func (bw *BackgroundWorker) WithEnvFromGroupsOrdered(names ...string) *BackgroundWorker {
+	for _, name := range names {
+		bw.EnvVars = append(bw.EnvVars, EnvFromGroup(name))
+	}
+	return bw
+}
+
+// WithEnvFileOrPanic adds the entries of a .env file, panicking if it cannot be loaded
+// Use LoadEnvInto to handle the error instead
+func (bw *BackgroundWorker) WithEnvFileOrPanic(path string) *BackgroundWorker {
+	if err := LoadEnvInto(bw, path); err != nil {
+		panic(err)
+	}
+	return bw
+}
+
 // WithStartCommand sets the start command for the private service
 func (ps *PrivateService) WithStartCommand(cmd string) *PrivateService {
 	ps.StartCommand = &cmd
 	return ps
 }
 
+// WithStartCommandArgs sets the start command from separate arguments, shell-quoting each one
+func (ps *PrivateService) WithStartCommandArgs(args ...string) *PrivateService {
+	return ps.WithStartCommand(ShellJoin(args...))
+}
+
 // WithPlan sets the instance plan for the private service
 func (ps *PrivateService) WithPlan(plan Plan) *PrivateService {
 	ps.Plan = &plan
 	return ps
 }
 
+// WithPlanString sets the plan from a string such as a CLI flag
+// An unknown plan or a database plan is recorded in Errors and leaves the plan unchanged
+func (ps *PrivateService) WithPlanString(plan string) *PrivateService {
+	parsed, err := ParsePlan(plan)
+	if err != nil {
+		ps.errs = append(ps.errs, err)
+		return ps
+	}
+	if !parsed.IsServicePlan() {
+		ps.errs = append(ps.errs, fmt.Errorf("plan %q is not a service plan", plan))
+		return ps
+	}
+	return ps.WithPlan(parsed)
+}
+
+// Errors returns the problems recorded while building the service
+func (ps *PrivateService) Errors() []error {
+	return ps.errs
+}
+
 // WithRegion sets the region for the private service
 func (ps *PrivateService) WithRegion(region Region) *PrivateService {
 	ps.Region = &region
 	return ps
 }
 
+// WithTags adds tags used to select services with SelectByTag
+func (ps *PrivateService) WithTags(tags ...string) *PrivateService {
+	ps.Tags = append(ps.Tags, tags...)
+	return ps
+}
+
 // WithGit configures Git repository settings for the private service
 func (ps *PrivateService) WithGit(repo string, branch ...string) *PrivateService {
 	ps.Git = &GitConfig{Repo: &repo}
@@ -778,6 +1105,15 @@ func (ps *PrivateService) WithBuild(buildCmd string) *PrivateService {
 	return ps
 }
 
+// WithPreviewEnvironment configures preview generation and the preview instance plan
+func (ps *PrivateService) WithPreviewEnvironment(generation PreviewGeneration, plan Plan) *PrivateService {
+	ps.Preview = &PreviewConfig{
+		Previews:    &ServicePreviews{Generation: string(generation)},
+		PreviewPlan: &plan,
+	}
+	return ps
+}
+
 // WithEnvVars adds environment variables to the private service
 func (ps *PrivateService) WithEnvVars(envVars ...EnvVar) *PrivateService {
 	ps.EnvVars = append(ps.EnvVars, envVars...)
@@ -793,6 +1129,36 @@ func (ps *PrivateService) WithEnv(key, value string) *PrivateService {
 	return ps
 }
 
+// WithDatabaseURL adds DATABASE_URL from the named database's connection string
+func (ps *PrivateService) WithDatabaseURL(dbName string) *PrivateService {
+	ps.EnvVars = append(ps.EnvVars, EnvDatabaseURL(dbName))
+	return ps
+}
+
+// WithDatabaseProperties adds one env var per database property, see EnvsFromDatabaseProperties
+func (ps *PrivateService) WithDatabaseProperties(prefix, dbName string, props ...DatabaseProperty) *PrivateService {
+	ps.EnvVars = append(ps.EnvVars, EnvsFromDatabaseProperties(prefix, dbName, props...)...)
+	return ps
+}
+
+// WithEnvFromGroupsOrdered includes environment variable groups in the given order
+// When groups define the same key, later groups take precedence over earlier ones
+func (ps *PrivateService) WithEnvFromGroupsOrdered(names ...string) *PrivateService {
+	for _, name := range names {
+		ps.EnvVars = append(ps.EnvVars, EnvFromGroup(name))
+	}
+	return ps
+}
+
+// WithEnvFileOrPanic adds the entries of a .env file, panicking if it cannot be loaded
+// Use LoadEnvInto to handle the error instead
+func (ps *PrivateService) WithEnvFileOrPanic(path string) *PrivateService {
+	if err := LoadEnvInto(ps, path); err != nil {
+		panic(err)
+	}
+	return ps
+}
+
 // NewCronJob creates a new CronJob
 func NewCronJob(name string, runtime Runtime, schedule string) *CronJob {
 	return &CronJob{
@@ -802,18 +1168,46 @@ func NewCronJob(name string, runtime Runtime, schedule string) *CronJob {
 	}
 }
 
+// WithDailySchedule runs the cron job once a day at hour:minute UTC
+// Out-of-range values are recorded, see Errors
+func (cj *CronJob) WithDailySchedule(hour, minute int) *CronJob {
+	schedule, err := dailyCron(hour, minute)
+	if err != nil {
+		cj.errs = append(cj.errs, err)
+		return cj
+	}
+	cj.Schedule = schedule
+	return cj
+}
+
+// Errors returns the problems recorded while building the cron job
+func (cj *CronJob) Errors() []error {
+	return cj.errs
+}
+
 // WithStartCommand sets the start command for the cron job
 func (cj *CronJob) WithStartCommand(cmd string) *CronJob {
 	cj.StartCommand = &cmd
 	return cj
 }
 
+// WithStartCommandArgs sets the start command from separate arguments, shell-quoting each one
+func (cj *CronJob) WithStartCommandArgs(args ...string) *CronJob {
+	return cj.WithStartCommand(ShellJoin(args...))
+}
+
 // WithRegion sets the region for the cron job
 func (cj *CronJob) WithRegion(region Region) *CronJob {
 	cj.Region = &region
 	return cj
 }
 
+// WithTags adds tags used to select services with SelectByTag
+func (cj *CronJob) WithTags(tags ...string) *CronJob {
+	cj.Tags = append(cj.Tags, tags...)
+	return cj
+}
+
 // WithGit configures Git repository settings for the cron job
 func (cj *CronJob) WithGit(repo string, branch ...string) *CronJob {
 	cj.Git = &GitConfig{Repo: &repo}
@@ -832,12 +1226,63 @@ func (cj *CronJob) WithBuild(buildCmd string) *CronJob {
 	return cj
 }
 
+// FromDockerImageOf copies the runtime and Docker configuration of a web service
+// so the job runs the same image without redefining it
+func (cj *CronJob) FromDockerImageOf(ws *WebService) *CronJob {
+	cj.Runtime = ws.Runtime
+	cj.Docker = nil
+	if ws.Docker != nil {
+		docker := cloneOf(*ws.Docker)
+		cj.Docker = &docker
+	}
+	return cj
+}
+
+// WithPreviewEnvironment configures preview generation and the preview instance plan
+func (cj *CronJob) WithPreviewEnvironment(generation PreviewGeneration, plan Plan) *CronJob {
+	cj.Preview = &PreviewConfig{
+		Previews:    &ServicePreviews{Generation: string(generation)},
+		PreviewPlan: &plan,
+	}
+	return cj
+}
+
 // WithEnvVars adds environment variables to the cron job
 func (cj *CronJob) WithEnvVars(envVars ...EnvVar) *CronJob {
 	cj.EnvVars = append(cj.EnvVars, envVars...)
 	return cj
 }
 
+// WithDatabaseURL adds DATABASE_URL from the named database's connection string
+func (cj *CronJob) WithDatabaseURL(dbName string) *CronJob {
+	cj.EnvVars = append(cj.EnvVars, EnvDatabaseURL(dbName))
+	return cj
+}
+
+// WithDatabaseProperties adds one env var per database property, see EnvsFromDatabaseProperties
+func (cj *CronJob) WithDatabaseProperties(prefix, dbName string, props ...DatabaseProperty) *CronJob {
+	cj.EnvVars = append(cj.EnvVars, EnvsFromDatabaseProperties(prefix, dbName, props...)...)
+	return cj
+}
+
+// WithEnvFromGroupsOrdered includes environment variable groups in the given order
+// When groups define the same key, later groups take precedence over earlier ones
+func (cj *CronJob) WithEnvFromGroupsOrdered(names ...string) *CronJob {
+	for _, name := range names {
+		cj.EnvVars = append(cj.EnvVars, EnvFromGroup(name))
+	}
+	return cj
+}
+
+// WithEnvFileOrPanic adds the entries of a .env file, panicking if it cannot be loaded
+// Use LoadEnvInto to handle the error instead
+func (cj *CronJob) WithEnvFileOrPanic(path string) *CronJob {
+	if err := LoadEnvInto(cj, path); err != nil {
+		panic(err)
+	}
+	return cj
+}
+
 // NewStaticSite creates a new StaticSite
 func NewStaticSite(name string) *StaticSite {
 	return &StaticSite{
@@ -878,12 +1323,64 @@ func (ss *StaticSite) WithRoutes(routes ...Route) *StaticSite {
 	return ss
 }
 
+// WithHeaderUnique adds headers, skipping any already present with identical fields
+func (ss *StaticSite) WithHeaderUnique(headers ...Header) *StaticSite {
+	if ss.StaticSite == nil {
+		ss.StaticSite = &StaticSiteConfig{}
+	}
+	for _, header := range headers {
+		if !containsHeader(ss.StaticSite.Headers, header) {
+			ss.StaticSite.Headers = append(ss.StaticSite.Headers, header)
+		}
+	}
+	return ss
+}
+
+// WithRouteUnique adds routing rules, skipping any already present with identical fields
+func (ss *StaticSite) WithRouteUnique(routes ...Route) *StaticSite {
+	if ss.StaticSite == nil {
+		ss.StaticSite = &StaticSiteConfig{}
+	}
+	for _, route := range routes {
+		if !containsRoute(ss.StaticSite.Routes, route) {
+			ss.StaticSite.Routes = append(ss.StaticSite.Routes, route)
+		}
+	}
+	return ss
+}
+
+// containsHeader reports whether headers includes an identical header
+func containsHeader(headers []Header, header Header) bool {
+	for _, existing := range headers {
+		if existing == header {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRoute reports whether routes includes an identical route
+func containsRoute(routes []Route, route Route) bool {
+	for _, existing := range routes {
+		if existing == route {
+			return true
+		}
+	}
+	return false
+}
+
 // WithRegion sets the region for the static site
 func (ss *StaticSite) WithRegion(region Region) *StaticSite {
 	ss.Region = &region
 	return ss
 }
 
+// WithTags adds tags used to select services with SelectByTag
+func (ss *StaticSite) WithTags(tags ...string) *StaticSite {
+	ss.Tags = append(ss.Tags, tags...)
+	return ss
+}
+
 // WithGit configures Git repository settings for the static site
 func (ss *StaticSite) WithGit(repo string, branch ...string) *StaticSite {
 	ss.Git = &GitConfig{Repo: &repo}
@@ -915,12 +1412,38 @@ func (kvs *KeyValueService) WithPlan(plan Plan) *KeyValueService {
 	return kvs
 }
 
+// WithPlanString sets the plan from a string such as a CLI flag
+// An unknown plan or a database plan is recorded in Errors and leaves the plan unchanged
+func (kvs *KeyValueService) WithPlanString(plan string) *KeyValueService {
+	parsed, err := ParsePlan(plan)
+	if err != nil {
+		kvs.errs = append(kvs.errs, err)
+		return kvs
+	}
+	if !parsed.IsServicePlan() {
+		kvs.errs = append(kvs.errs, fmt.Errorf("plan %q is not a service plan", plan))
+		return kvs
+	}
+	return kvs.WithPlan(parsed)
+}
+
+// Errors returns the problems recorded while building the service
+func (kvs *KeyValueService) Errors() []error {
+	return kvs.errs
+}
+
 // WithRegion sets the region for the key-value service
 func (kvs *KeyValueService) WithRegion(region Region) *KeyValueService {
 	kvs.Region = &region
 	return kvs
 }
 
+// WithTags adds tags used to select services with SelectByTag
+func (kvs *KeyValueService) WithTags(tags ...string) *KeyValueService {
+	kvs.Tags = append(kvs.Tags, tags...)
+	return kvs
+}
+
 // WithIPAllowList sets the IP allow list
 func (kvs *KeyValueService) WithIPAllowList(allowList ...IPAllow) *KeyValueService {
 	if kvs.KeyValue == nil {
@@ -947,6 +1470,15 @@ func (kvs *KeyValueService) WithMaxMemoryPolicy(policy MaxMemoryPolicy) *KeyValu
 	return kvs
 }
 
+// WithPreviewEnvironment configures preview generation and the preview instance plan
+func (kvs *KeyValueService) WithPreviewEnvironment(generation PreviewGeneration, plan Plan) *KeyValueService {
+	kvs.Preview = &PreviewConfig{
+		Previews:    &ServicePreviews{Generation: string(generation)},
+		PreviewPlan: &plan,
+	}
+	return kvs
+}
+
 // Helper functions for creating environment variables
 
 // Env creates a simple environment variable
@@ -965,6 +1497,26 @@ func EnvFromDatabase(key, dbName string, property DatabaseProperty) EnvVar {
 	}
 }
 
+// EnvsFromDatabaseProperties creates one environment variable per database property,
+// keyed by prefix plus the uppercased property name (DB_ and host give DB_HOST)
+func EnvsFromDatabaseProperties(prefix, dbName string, props ...DatabaseProperty) []EnvVar {
+	envVars := make([]EnvVar, 0, len(props))
+	for _, prop := range props {
+		envVars = append(envVars, EnvFromDatabase(prefix+strings.ToUpper(string(prop)), dbName, prop))
+	}
+	return envVars
+}
+
+// EnvDatabaseURL creates the conventional DATABASE_URL variable from a database's connection string
+func EnvDatabaseURL(dbName string) EnvVar {
+	return EnvFromDatabase("DATABASE_URL", dbName, DatabasePropertyConnectionString)
+}
+
+// EnvInternalDatabaseURL creates DATABASE_URL from a database's internal connection string
+func EnvInternalDatabaseURL(dbName string) EnvVar {
+	return EnvFromDatabase("DATABASE_URL", dbName, DatabasePropertyInternalConnectionString)
+}
+
 // EnvFromService creates an environment variable from a service property
 func EnvFromService(key, serviceName string, serviceType ServiceType, property ServiceProperty) EnvVar {
 	return EnvVar{
@@ -993,4 +1545,25 @@ func EnvGenerated(key string) EnvVar {
 		Key:           &key,
 		GenerateValue: &generate,
 	}
-}
\ No newline at end of file
+}
+
+// ShellJoin quotes each argument for a POSIX shell and joins them into a single command string
+// Render only accepts commands as strings, so arguments containing spaces or quotes must be escaped
+func ShellJoin(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellSafePattern matches arguments that need no quoting
+var shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote wraps an argument in single quotes when it contains shell metacharacters
+func shellQuote(arg string) string {
+	if shellSafePattern.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+}
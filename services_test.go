@@ -0,0 +1,504 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWithEnvFromGroupsOrdered(t *testing.T) {
+	groups := []string{"base", "team", "overrides", "another"}
+
+	api := NewWebService("api", RuntimeNode).
+		WithEnv("NODE_ENV", "production").
+		WithEnvFromGroupsOrdered(groups...)
+	worker := NewBackgroundWorker("worker", RuntimePython).
+		WithEnvFromGroupsOrdered(groups...)
+
+	bp := NewBlueprint().WithServices(api, worker)
+
+	data, err := bp.ToYAMLBytes()
+	if err != nil {
+		t.Fatalf("failed to marshal blueprint: %v", err)
+	}
+
+	var parsed struct {
+		Services []struct {
+			Name    string `yaml:"name"`
+			EnvVars []struct {
+				FromGroup string `yaml:"fromGroup"`
+			} `yaml:"envVars"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to parse YAML: %v", err)
+	}
+
+	if len(parsed.Services) != 2 {
+		t.Fatalf("expected 2 services, got %d", len(parsed.Services))
+	}
+
+	for _, service := range parsed.Services {
+		var order []string
+		for _, envVar := range service.EnvVars {
+			if envVar.FromGroup != "" {
+				order = append(order, envVar.FromGroup)
+			}
+		}
+		if !reflect.DeepEqual(order, groups) {
+			t.Errorf("service %s: expected fromGroup order %v, got %v", service.Name, groups, order)
+		}
+	}
+}
+
+func TestWithPreviewEnvironment(t *testing.T) {
+	api := NewWebService("api", RuntimeNode).
+		WithStartCommand("npm start").
+		WithPreviewEnvironment(PreviewGenerationAutomatic, PlanStarter)
+
+	service := api.ToService()
+	if service.Previews == nil || service.Previews.Generation != string(PreviewGenerationAutomatic) {
+		t.Errorf("expected previews generation %q, got %+v", PreviewGenerationAutomatic, service.Previews)
+	}
+	if service.PreviewPlan == nil || *service.PreviewPlan != PlanStarter {
+		t.Errorf("expected preview plan %q, got %v", PlanStarter, service.PreviewPlan)
+	}
+	if errors := ValidateBlueprint(NewBlueprint().WithServices(api)); len(errors) != 0 {
+		t.Errorf("unexpected validation errors: %v", errors)
+	}
+
+	// A database plan is not a valid preview plan for a service
+	worker := NewBackgroundWorker("worker", RuntimePython).
+		WithPreviewEnvironment(PreviewGenerationAutomatic, PlanBasic1GB)
+	errors := ValidateBlueprint(NewBlueprint().WithServices(worker))
+	expected := []string{"service worker previewPlan basic-1gb is not a service plan"}
+	if !reflect.DeepEqual(errors, expected) {
+		t.Errorf("expected %v, got %v", expected, errors)
+	}
+}
+
+func TestWithStartCommandArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "plain arguments",
+			args:     []string{"node", "server.js", "--port=8080"},
+			expected: "node server.js --port=8080",
+		},
+		{
+			name:     "argument with spaces",
+			args:     []string{"python", "-c", "print('hello world')"},
+			expected: `python -c 'print('"'"'hello world'"'"')'`,
+		},
+		{
+			name:     "empty argument",
+			args:     []string{"echo", ""},
+			expected: "echo ''",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewBackgroundWorker("worker", RuntimePython).
+				WithStartCommandArgs(tt.args...).
+				ToService()
+
+			if service.StartCommand == nil || *service.StartCommand != tt.expected {
+				t.Errorf("expected start command %q, got %v", tt.expected, service.StartCommand)
+			}
+		})
+	}
+}
+
+func TestToServiceCarriesAllFields(t *testing.T) {
+	builders := []ServiceBuilder{
+		&WebService{},
+		&BackgroundWorker{},
+		&PrivateService{},
+		&CronJob{},
+		&StaticSite{},
+		&KeyValueService{},
+	}
+
+	for _, builder := range builders {
+		builderValue := reflect.ValueOf(builder).Elem()
+		t.Run(builderValue.Type().Name(), func(t *testing.T) {
+			seed := 0
+			populate(builderValue, &seed)
+
+			service := reflect.ValueOf(builder.ToService()).Elem()
+			for name, want := range flattenBuilderFields(builderValue) {
+				got := service.FieldByName(name)
+				if !got.IsValid() {
+					t.Errorf("Service has no field %s", name)
+					continue
+				}
+				if !reflect.DeepEqual(indirect(got).Interface(), indirect(want).Interface()) {
+					t.Errorf("field %s not carried over: want %v, got %v", name, indirect(want), indirect(got))
+				}
+			}
+		})
+	}
+}
+
+// populate fills every exported field with distinct non-zero values
+// Slices get two elements so ordering is preserved in comparisons
+func populate(v reflect.Value, seed *int) {
+	*seed++
+	switch v.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		populate(v.Elem(), seed)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				populate(v.Field(i), seed)
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < v.Len(); i++ {
+			populate(v.Index(i), seed)
+		}
+	case reflect.String:
+		v.SetString(fmt.Sprintf("value-%d", *seed))
+	case reflect.Int:
+		v.SetInt(int64(*seed))
+	case reflect.Bool:
+		v.SetBool(true)
+	}
+}
+
+// flattenBuilderFields returns builder fields by name, expanding the *Config groups
+func flattenBuilderFields(v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Type.Kind() == reflect.Ptr && strings.HasSuffix(field.Type.Elem().Name(), "Config") {
+			for name, nested := range flattenBuilderFields(value.Elem()) {
+				fields[name] = nested
+			}
+			continue
+		}
+		fields[field.Name] = value
+	}
+	return fields
+}
+
+// indirect dereferences pointers so pointer and value fields compare equal
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func TestEnvDatabaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVar   EnvVar
+		expected EnvVar
+	}{
+		{
+			name:     "connection string",
+			envVar:   EnvDatabaseURL("main-db"),
+			expected: EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyConnectionString),
+		},
+		{
+			name:     "internal connection string",
+			envVar:   EnvInternalDatabaseURL("main-db"),
+			expected: EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyInternalConnectionString),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.envVar, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, tt.envVar)
+			}
+		})
+	}
+
+	service := NewWebService("api", RuntimeNode).WithDatabaseURL("main-db").ToService()
+	expected := []EnvVar{EnvFromDatabase("DATABASE_URL", "main-db", DatabasePropertyConnectionString)}
+	if !reflect.DeepEqual(service.EnvVars, expected) {
+		t.Errorf("expected %+v, got %+v", expected, service.EnvVars)
+	}
+}
+
+func TestWithoutHealthCheck(t *testing.T) {
+	api := NewWebService("api", RuntimeNode).WithHealthCheckPath("/health")
+	if api.HealthCheckPath == nil || *api.HealthCheckPath != "/health" {
+		t.Fatalf("expected health check path /health, got %v", api.HealthCheckPath)
+	}
+
+	api.WithoutHealthCheck()
+	if api.HealthCheckPath != nil {
+		t.Errorf("expected health check path to be cleared, got %q", *api.HealthCheckPath)
+	}
+
+	yamlStr, err := NewBlueprint().WithServices(api).ToYAMLString()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if strings.Contains(yamlStr, "healthCheckPath") {
+		t.Errorf("expected no healthCheckPath in YAML:\n%s", yamlStr)
+	}
+}
+
+func TestWithRouteAndHeaderUnique(t *testing.T) {
+	spa := Route{Type: string(RouteTypeRewrite), Source: "/*", Destination: "/index.html"}
+	legacy := Route{Type: string(RouteTypeRedirect), Source: "/old", Destination: "/new"}
+	cache := Header{Path: "/*", Name: "Cache-Control", Value: "no-cache"}
+
+	site := NewStaticSite("frontend").
+		WithPublishPath("./dist").
+		WithRouteUnique(spa).
+		WithRouteUnique(spa, legacy).
+		WithHeaderUnique(cache, cache)
+
+	// Simulate re-running a generator
+	site.WithRouteUnique(spa).WithHeaderUnique(cache)
+
+	service := site.ToService()
+	if !reflect.DeepEqual(service.Routes, []Route{spa, legacy}) {
+		t.Errorf("expected routes [spa legacy], got %+v", service.Routes)
+	}
+	if !reflect.DeepEqual(service.Headers, []Header{cache}) {
+		t.Errorf("expected a single header, got %+v", service.Headers)
+	}
+
+	// Entries that differ in any field are kept
+	site.WithHeaderUnique(Header{Path: "/*", Name: "Cache-Control", Value: "max-age=60"})
+	if len(site.ToService().Headers) != 2 {
+		t.Errorf("expected differing header to be added, got %+v", site.ToService().Headers)
+	}
+}
+
+func TestInRegion(t *testing.T) {
+	bp := NewBlueprint().WithServices(InRegion(RegionFrankfurt,
+		NewWebService("api", RuntimeNode),
+		NewBackgroundWorker("worker", RuntimeNode).WithRegion(RegionOregon),
+		NewPrivateService("internal", RuntimeGo),
+		NewCronJob("cleanup", RuntimeNode, "0 3 * * *"),
+		NewKeyValueService("cache"),
+	)...)
+
+	for _, service := range bp.Services {
+		if service.Region == nil || *service.Region != RegionFrankfurt {
+			t.Errorf("expected service %s in %s, got %v", service.Name, RegionFrankfurt, service.Region)
+		}
+	}
+}
+
+func TestWithDiskDeferredErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  *WebService
+		expected []string
+	}{
+		{
+			name:     "normal size",
+			service:  NewWebService("api", RuntimeNode).WithDisk("data", "/var/data", 50),
+			expected: nil,
+		},
+		{
+			name:     "absurd size",
+			service:  NewWebService("api", RuntimeNode).WithDisk("data", "/var/data", 100000),
+			expected: []string{"disk data size 100000GB exceeds the 1000GB maximum"},
+		},
+		{
+			name:     "absurd size from a spec",
+			service:  NewWebService("api", RuntimeNode).WithDiskSpec(Disk{Name: "logs", MountPath: "/logs", SizeGB: intPtr(5000)}),
+			expected: []string{"disk logs size 5000GB exceeds the 1000GB maximum"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, err := range tt.service.Errors() {
+				messages = append(messages, err.Error())
+			}
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+
+			// The chain keeps going and the disk is still set
+			if tt.service.WithPlan(PlanStandard).ToService().Disk == nil {
+				t.Error("expected the disk to be set")
+			}
+		})
+	}
+}
+
+func TestWithPlanString(t *testing.T) {
+	t.Run("valid plan", func(t *testing.T) {
+		ws := NewWebService("api", RuntimeNode).WithPlanString("standard")
+		if len(ws.Errors()) != 0 || ws.Plan == nil || *ws.Plan != PlanStandard {
+			t.Errorf("expected plan standard with no errors, got %v (%v)", ws.Plan, ws.Errors())
+		}
+
+		db := NewDatabase("main-db").WithPlanString("basic-1gb")
+		if len(db.Errors()) != 0 || db.Plan == nil || *db.Plan != PlanBasic1GB {
+			t.Errorf("expected plan basic-1gb with no errors, got %v (%v)", db.Plan, db.Errors())
+		}
+	})
+
+	t.Run("invalid plan", func(t *testing.T) {
+		bw := NewBackgroundWorker("worker", RuntimeNode).WithPlan(PlanStarter).WithPlanString("huge")
+		if *bw.Plan != PlanStarter {
+			t.Errorf("expected the plan to be unchanged, got %s", *bw.Plan)
+		}
+		if errs := bw.Errors(); len(errs) != 1 || errs[0].Error() != `unsupported plan "huge"` {
+			t.Errorf("expected one unsupported plan error, got %v", errs)
+		}
+
+		db := NewDatabase("main-db").WithPlanString("huge")
+		if db.Plan != nil || len(db.Errors()) != 1 {
+			t.Errorf("expected no plan and one error, got %v (%v)", db.Plan, db.Errors())
+		}
+	})
+
+	t.Run("plan for the wrong kind of resource", func(t *testing.T) {
+		ws := NewWebService("api", RuntimeNode).WithPlanString("basic-1gb")
+		bw := NewBackgroundWorker("worker", RuntimeNode).WithPlanString("pro-8gb")
+		ps := NewPrivateService("internal", RuntimeNode).WithPlanString("basic-256mb")
+		kvs := NewKeyValueService("cache").WithPlanString("pro-16gb")
+		db := NewDatabase("main-db").WithPlanString("standard")
+
+		tests := []struct {
+			name     string
+			plan     *Plan
+			errs     []error
+			expected string
+		}{
+			{"web service", ws.Plan, ws.Errors(), `plan "basic-1gb" is not a service plan`},
+			{"background worker", bw.Plan, bw.Errors(), `plan "pro-8gb" is not a service plan`},
+			{"private service", ps.Plan, ps.Errors(), `plan "basic-256mb" is not a service plan`},
+			{"key value", kvs.Plan, kvs.Errors(), `plan "pro-16gb" is not a service plan`},
+			{"database", db.Plan, db.Errors(), `plan "standard" is not a database plan`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.plan != nil {
+					t.Errorf("expected the plan to stay unset, got %s", *tt.plan)
+				}
+				if len(tt.errs) != 1 || tt.errs[0].Error() != tt.expected {
+					t.Errorf("expected error %q, got %v", tt.expected, tt.errs)
+				}
+			})
+		}
+
+		if free := NewDatabase("main-db").WithPlanString("free"); len(free.Errors()) != 0 || *free.Plan != PlanFree {
+			t.Errorf("expected the free plan to be accepted for databases, got %v (%v)", free.Plan, free.Errors())
+		}
+	})
+}
+
+func TestFromDockerImageOf(t *testing.T) {
+	web := NewWebService("api", RuntimeImage).WithDockerImage("ghcr.io/example/api:v1.2.3")
+	web.Docker.RegistryCredential = &RegistryCredential{FromRegistryCreds: &RegistryCredsRef{Name: "ghcr"}}
+
+	cron := NewCronJob("nightly", RuntimeNode, "0 3 * * *").
+		FromDockerImageOf(web).
+		WithStartCommand("./bin/nightly")
+
+	if cron.Runtime != RuntimeImage {
+		t.Errorf("expected runtime %s, got %s", RuntimeImage, cron.Runtime)
+	}
+	if !reflect.DeepEqual(cron.Docker, web.Docker) {
+		t.Errorf("expected docker config %+v, got %+v", web.Docker, cron.Docker)
+	}
+
+	// The job gets its own copy of the config
+	cron.Docker.Image.URL = "ghcr.io/example/api:v2"
+	if web.Docker.Image.URL != "ghcr.io/example/api:v1.2.3" {
+		t.Errorf("expected the web service image to be unchanged, got %s", web.Docker.Image.URL)
+	}
+}
+
+func TestWebServiceWithDockerImageDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	ws := NewWebService("api", RuntimeImage).WithDockerImageDigest("ghcr.io/acme/api", digest)
+	if len(ws.Errors()) != 0 {
+		t.Errorf("expected no errors, got %v", ws.Errors())
+	}
+	if got := ws.ToService().Image.URL; got != "ghcr.io/acme/api@"+digest {
+		t.Errorf("expected pinned image url, got %q", got)
+	}
+
+	ws = NewWebService("api", RuntimeImage).WithDockerImageDigest("ghcr.io/acme/api", "latest")
+	if len(ws.Errors()) != 1 {
+		t.Errorf("expected one error for a malformed digest, got %v", ws.Errors())
+	}
+}
+
+func TestEnvVarGroupWithEnvVarsDedup(t *testing.T) {
+	group := NewEnvVarGroup("shared").
+		WithEnv("LOG_LEVEL", "info").
+		WithEnv("REGION", "oregon").
+		WithEnvVarsDedup(Env("LOG_LEVEL", "debug"), Env("TIMEOUT", "30"))
+
+	expected := []EnvVar{Env("LOG_LEVEL", "debug"), Env("REGION", "oregon"), Env("TIMEOUT", "30")}
+	if !reflect.DeepEqual(group.EnvVars, expected) {
+		t.Errorf("expected %+v, got %+v", expected, group.EnvVars)
+	}
+}
+
+func TestEnvsFromDatabaseProperties(t *testing.T) {
+	props := []DatabaseProperty{DatabasePropertyHost, DatabasePropertyPort, DatabasePropertyUser, DatabasePropertyPassword, DatabasePropertyDatabase}
+	envVars := EnvsFromDatabaseProperties("DB_", "main-db", props...)
+
+	expectedKeys := []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_DATABASE"}
+	if len(envVars) != len(expectedKeys) {
+		t.Fatalf("expected %d env vars, got %d", len(expectedKeys), len(envVars))
+	}
+	for i, envVar := range envVars {
+		if *envVar.Key != expectedKeys[i] {
+			t.Errorf("expected key %s, got %s", expectedKeys[i], *envVar.Key)
+		}
+		if envVar.FromDatabase == nil || envVar.FromDatabase.Name != "main-db" || envVar.FromDatabase.Property != props[i] {
+			t.Errorf("expected fromDatabase main-db %s, got %+v", props[i], envVar.FromDatabase)
+		}
+	}
+
+	worker := NewBackgroundWorker("worker", RuntimeNode).WithDatabaseProperties("DB_", "main-db", DatabasePropertyHost, DatabasePropertyPort)
+	if !reflect.DeepEqual(worker.EnvVars, envVars[:2]) {
+		t.Errorf("expected builder env vars %+v, got %+v", envVars[:2], worker.EnvVars)
+	}
+}
+
+func TestWebServiceWithScalingSpec(t *testing.T) {
+	spec := &Scaling{
+		MinInstances:        intPtr(2),
+		MaxInstances:        intPtr(6),
+		TargetCPUPercent:    intPtr(70),
+		TargetMemoryPercent: intPtr(80),
+	}
+
+	ws := NewWebService("api", RuntimeNode).WithScalingSpec(spec)
+	if len(ws.Errors()) != 0 {
+		t.Errorf("expected no errors, got %v", ws.Errors())
+	}
+	if got := ws.ToService().Scaling; !reflect.DeepEqual(got, spec) {
+		t.Errorf("expected scaling %+v, got %+v", spec, got)
+	}
+
+	inverted := NewWebService("api", RuntimeNode).WithScalingSpec(&Scaling{MinInstances: intPtr(5), MaxInstances: intPtr(2)})
+	if len(inverted.Errors()) != 1 {
+		t.Errorf("expected one error for inverted bounds, got %v", inverted.Errors())
+	}
+}
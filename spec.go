@@ -0,0 +1,96 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Spec is a plain description of a blueprint, decoupled from the fluent builders
+// It is JSON-tagged so external systems can serialize it
+type Spec struct {
+	Services []ServiceSpec `json:"services"`
+}
+
+// ServiceSpec describes a single service in a Spec
+type ServiceSpec struct {
+	Type     string            `json:"type"`
+	Name     string            `json:"name"`
+	Runtime  string            `json:"runtime,omitempty"`
+	Schedule string            `json:"schedule,omitempty"`
+	Env      map[string]string `json:"env,omitempty"`
+}
+
+// BlueprintFromSpec builds a blueprint from a plain description
+func BlueprintFromSpec(spec Spec) (*Blueprint, error) {
+	bp := NewBlueprint()
+
+	for i, serviceSpec := range spec.Services {
+		if serviceSpec.Name == "" {
+			return nil, fmt.Errorf("service %d missing name", i)
+		}
+
+		service, err := serviceFromSpec(serviceSpec)
+		if err != nil {
+			return nil, err
+		}
+		bp.WithServices(service)
+	}
+
+	return bp, nil
+}
+
+// serviceFromSpec builds the matching service builder for a service spec
+func serviceFromSpec(spec ServiceSpec) (ServiceBuilder, error) {
+	runtime := Runtime(spec.Runtime)
+	if spec.Type != string(ServiceTypeKeyValue) && !isKnownRuntime(runtime) {
+		return nil, fmt.Errorf("service %s has unknown runtime %q", spec.Name, spec.Runtime)
+	}
+
+	// Environment variables in a stable order
+	keys := make([]string, 0, len(spec.Env))
+	for key := range spec.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	envVars := make([]EnvVar, len(keys))
+	for i, key := range keys {
+		envVars[i] = Env(key, spec.Env[key])
+	}
+
+	switch ServiceType(spec.Type) {
+	case ServiceTypeWeb:
+		if runtime == RuntimeStatic {
+			if len(envVars) > 0 {
+				return nil, fmt.Errorf("static site %s does not support env vars", spec.Name)
+			}
+			return NewStaticSite(spec.Name), nil
+		}
+		return NewWebService(spec.Name, runtime).WithEnvVars(envVars...), nil
+	case ServiceTypeWorker:
+		return NewBackgroundWorker(spec.Name, runtime).WithEnvVars(envVars...), nil
+	case ServiceTypePServ:
+		return NewPrivateService(spec.Name, runtime).WithEnvVars(envVars...), nil
+	case ServiceTypeCron:
+		if spec.Schedule == "" {
+			return nil, fmt.Errorf("cron service %s missing schedule", spec.Name)
+		}
+		return NewCronJob(spec.Name, runtime, spec.Schedule).WithEnvVars(envVars...), nil
+	case ServiceTypeKeyValue:
+		if len(envVars) > 0 {
+			return nil, fmt.Errorf("keyvalue service %s does not support env vars", spec.Name)
+		}
+		return NewKeyValueService(spec.Name), nil
+	default:
+		return nil, fmt.Errorf("service %s has unknown type %q", spec.Name, spec.Type)
+	}
+}
+
+// isKnownRuntime reports whether the runtime is one of the supported runtimes
+func isKnownRuntime(runtime Runtime) bool {
+	switch runtime {
+	case RuntimeNode, RuntimePython, RuntimeRuby, RuntimeGo, RuntimeRust,
+		RuntimeDocker, RuntimeStatic, RuntimeImage:
+		return true
+	}
+	return false
+}
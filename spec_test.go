@@ -0,0 +1,69 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBlueprintFromSpec(t *testing.T) {
+	data := []byte(`{
+		"services": [
+			{"type": "web", "name": "api", "runtime": "node", "env": {"PORT": "8080", "NODE_ENV": "production"}},
+			{"type": "worker", "name": "jobs", "runtime": "python"}
+		]
+	}`)
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+
+	bp, err := BlueprintFromSpec(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := NewBlueprint().WithServices(
+		NewWebService("api", RuntimeNode).
+			WithEnv("NODE_ENV", "production").
+			WithEnv("PORT", "8080"),
+		NewBackgroundWorker("jobs", RuntimePython),
+	)
+	if !bp.Equal(expected) {
+		t.Errorf("expected %+v, got %+v", expected.Services, bp.Services)
+	}
+}
+
+func TestBlueprintFromSpecErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     ServiceSpec
+		expected string
+	}{
+		{
+			name:     "unknown type",
+			spec:     ServiceSpec{Type: "lambda", Name: "api", Runtime: "node"},
+			expected: `service api has unknown type "lambda"`,
+		},
+		{
+			name:     "unknown runtime",
+			spec:     ServiceSpec{Type: "web", Name: "api", Runtime: "cobol"},
+			expected: `service api has unknown runtime "cobol"`,
+		},
+		{
+			name:     "cron without schedule",
+			spec:     ServiceSpec{Type: "cron", Name: "cleanup", Runtime: "go"},
+			expected: "cron service cleanup missing schedule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BlueprintFromSpec(Spec{Services: []ServiceSpec{tt.spec}})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// BlueprintSummary holds aggregate statistics about a blueprint
+type BlueprintSummary struct {
+	Services       int            `json:"services"`
+	ServicesByType map[string]int `json:"servicesByType"` // static sites are counted as "static"
+	Databases      int            `json:"databases"`
+	EnvVarGroups   int            `json:"envVarGroups"`
+	Regions        []string       `json:"regions"`
+	Previews       bool           `json:"previews"`
+	SecretCount    int            `json:"secretCount"` // env vars with sync: false
+}
+
+// Summary returns aggregate statistics about the blueprint
+func (bp *Blueprint) Summary() BlueprintSummary {
+	summary := BlueprintSummary{
+		ServicesByType: make(map[string]int),
+		Regions:        []string{},
+	}
+
+	if bp == nil {
+		return summary
+	}
+
+	summary.Services = len(bp.Services)
+	summary.Databases = len(bp.Databases)
+	summary.EnvVarGroups = len(bp.EnvVarGroups)
+	summary.Previews = bp.Previews != nil && previewsEnabled(bp.Previews.Generation)
+
+	regions := make(map[string]bool)
+	addRegion := func(region *Region) {
+		if region != nil {
+			regions[string(*region)] = true
+		}
+	}
+	countSecrets := func(envVars []EnvVar) {
+		for _, envVar := range envVars {
+			if envVar.Sync != nil && !*envVar.Sync {
+				summary.SecretCount++
+			}
+		}
+	}
+
+	for _, service := range bp.Services {
+		if isStaticSite(service) {
+			summary.ServicesByType["static"]++
+		} else {
+			summary.ServicesByType[string(service.Type)]++
+		}
+		addRegion(service.Region)
+		countSecrets(service.EnvVars)
+	}
+	for _, db := range bp.Databases {
+		addRegion(db.Region)
+	}
+	for _, group := range bp.EnvVarGroups {
+		countSecrets(group.EnvVars)
+	}
+
+	for region := range regions {
+		summary.Regions = append(summary.Regions, region)
+	}
+	sort.Strings(summary.Regions)
+
+	return summary
+}
+
+// StatsJSON returns the blueprint summary as JSON for dashboards
+// Map keys and regions are sorted, so the output is stable
+func (bp *Blueprint) StatsJSON() ([]byte, error) {
+	data, err := json.Marshal(bp.Summary())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blueprint summary to JSON: %w", err)
+	}
+	return data, nil
+}
+
+// RegionDefault is the ResourcesByRegion key for resources without an explicit region
+// They run in the account's default region; it is not a valid region value
+const RegionDefault Region = "default"
+
+// ResourcesByRegion maps each region to the names of the services and databases in it
+// Static sites are served from a CDN and are not listed
+func (bp *Blueprint) ResourcesByRegion() map[Region][]string {
+	byRegion := make(map[Region][]string)
+
+	if bp == nil {
+		return byRegion
+	}
+
+	add := func(region *Region, name string) {
+		key := RegionDefault
+		if region != nil {
+			key = *region
+		}
+		byRegion[key] = append(byRegion[key], name)
+	}
+
+	for _, service := range bp.Services {
+		if !isStaticSite(service) {
+			add(service.Region, service.Name)
+		}
+	}
+	for _, db := range bp.Databases {
+		add(db.Region, db.Name)
+	}
+
+	return byRegion
+}
+
+// EstimateCost estimates the monthly cost of the blueprint from a caller-supplied price table
+// Each service costs its plan price times its instance count (numInstances, or the
+// autoscaling maximum); each database costs its plan price
+// Resources without a plan are priced at the plan Render assigns by default, starter for
+// services and basic-256mb for databases; static sites are free and not counted
+func (bp *Blueprint) EstimateCost(priceTable map[Plan]float64) (total float64, perResource map[string]float64, err error) {
+	perResource = make(map[string]float64)
+
+	if bp == nil {
+		return 0, perResource, nil
+	}
+
+	add := func(name string, plan *Plan, defaultPlan Plan, instances int) error {
+		resolved := defaultPlan
+		if plan != nil {
+			resolved = *plan
+		}
+		price, ok := priceTable[resolved]
+		if !ok {
+			return fmt.Errorf("no price for plan %s used by %s", resolved, name)
+		}
+		cost := price * float64(instances)
+		perResource[name] += cost
+		total += cost
+		return nil
+	}
+
+	for _, service := range bp.Services {
+		if isStaticSite(service) {
+			continue
+		}
+		instances := 1
+		if service.NumInstances != nil {
+			instances = *service.NumInstances
+		} else if service.Scaling != nil && service.Scaling.MaxInstances != nil {
+			instances = *service.Scaling.MaxInstances
+		}
+		if err := add(service.Name, service.Plan, PlanStarter, instances); err != nil {
+			return 0, nil, err
+		}
+	}
+	for _, db := range bp.Databases {
+		if err := add(db.Name, db.Plan, PlanBasic256MB, 1); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return total, perResource, nil
+}
+
+// PublicSurfaceReport lists the parts of a blueprint reachable from the internet
+type PublicSurfaceReport struct {
+	WebServices []PublicWebService `json:"webServices"`
+	DataStores  []PublicDataStore  `json:"dataStores"`
+}
+
+// PublicWebService is a web service, including static sites, and the domains it serves
+type PublicWebService struct {
+	Name    string   `json:"name"`
+	Domains []string `json:"domains,omitempty"`
+}
+
+// PublicDataStore is a database or key-value store that accepts connections from any address
+type PublicDataStore struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // "database" or "keyvalue"
+}
+
+// PublicSurface returns the externally-reachable web services and data stores for security review
+// Data stores are reported when their allow list is omitted, since Render then allows all
+// addresses, or when it includes 0.0.0.0/0 or ::/0
+func (bp *Blueprint) PublicSurface() PublicSurfaceReport {
+	report := PublicSurfaceReport{
+		WebServices: []PublicWebService{},
+		DataStores:  []PublicDataStore{},
+	}
+
+	if bp == nil {
+		return report
+	}
+
+	for _, service := range bp.Services {
+		switch service.Type {
+		case ServiceTypeWeb:
+			report.WebServices = append(report.WebServices, PublicWebService{Name: service.Name, Domains: service.Domains})
+		case ServiceTypeKeyValue, ServiceTypeRedis:
+			if allowsPublicAccess(service.IPAllowList) {
+				report.DataStores = append(report.DataStores, PublicDataStore{Name: service.Name, Type: string(ServiceTypeKeyValue)})
+			}
+		}
+	}
+	for _, db := range bp.Databases {
+		if allowsPublicAccess(db.IPAllowList) {
+			report.DataStores = append(report.DataStores, PublicDataStore{Name: db.Name, Type: string(ResourceKindDatabase)})
+		}
+	}
+
+	return report
+}
+
+// allowsPublicAccess reports whether an allow list admits any address
+// A nil list leaves Render's default in place, which admits every address
+func allowsPublicAccess(allowList []IPAllow) bool {
+	if allowList == nil {
+		return true
+	}
+	for _, entry := range allowList {
+		if entry.Source == "0.0.0.0/0" || entry.Source == "::/0" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package render
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithRegion(RegionOregon).WithEnvVars(EnvSecret("API_KEY")),
+			NewWebService("admin", RuntimeNode).WithRegion(RegionFrankfurt),
+			NewBackgroundWorker("worker", RuntimePython).WithRegion(RegionOregon),
+			NewStaticSite("frontend"),
+		).
+		WithDatabases(NewDatabase("main-db").WithRegion(RegionOregon)).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithSecret("STRIPE_KEY").WithEnv("LOG_LEVEL", "info")).
+		WithPreviews(PreviewGenerationAutomatic)
+
+	expected := BlueprintSummary{
+		Services:       4,
+		ServicesByType: map[string]int{"web": 2, "worker": 1, "static": 1},
+		Databases:      1,
+		EnvVarGroups:   1,
+		Regions:        []string{"frankfurt", "oregon"},
+		Previews:       true,
+		SecretCount:    2,
+	}
+	if summary := bp.Summary(); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected %+v, got %+v", expected, summary)
+	}
+
+	// A previews block only counts when its generation creates preview environments
+	for _, generation := range []string{"", "off", string(PreviewGenerationNone)} {
+		bp.Previews = &Previews{Generation: generation}
+		if bp.Summary().Previews {
+			t.Errorf("expected previews to be disabled for generation %q", generation)
+		}
+		data, err := bp.StatsJSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(string(data), `"previews":false`) {
+			t.Errorf("expected StatsJSON to report previews disabled for generation %q, got %s", generation, data)
+		}
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithRegion(RegionOregon),
+			NewKeyValueService("cache"),
+		).
+		WithDatabases(NewDatabase("main-db"))
+
+	data, err := bp.StatsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var stats map[string]interface{}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatalf("StatsJSON output is not valid JSON: %v\n%s", err, data)
+	}
+
+	for field, expected := range map[string]float64{
+		"services":     2,
+		"databases":    1,
+		"envVarGroups": 0,
+		"secretCount":  0,
+	} {
+		value, ok := stats[field].(float64)
+		if !ok || value != expected {
+			t.Errorf("expected %s to be %v, got %v", field, expected, stats[field])
+		}
+	}
+
+	// Output is stable across calls
+	again, _ := bp.StatsJSON()
+	if string(again) != string(data) {
+		t.Errorf("expected stable output, got %s and %s", data, again)
+	}
+}
+
+func TestResourcesByRegion(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithRegion(RegionOregon),
+			NewBackgroundWorker("worker", RuntimeNode).WithRegion(RegionFrankfurt),
+			NewKeyValueService("cache"),
+			NewStaticSite("frontend").WithPublishPath("./dist"),
+		).
+		WithDatabases(
+			NewDatabase("main-db").WithRegion(RegionOregon),
+			NewDatabase("scratch-db"),
+		)
+
+	expected := map[Region][]string{
+		RegionOregon:    {"api", "main-db"},
+		RegionFrankfurt: {"worker"},
+		RegionDefault:   {"cache", "scratch-db"},
+	}
+	if got := bp.ResourcesByRegion(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	prices := map[Plan]float64{
+		PlanStarter:  7,
+		PlanStandard: 25,
+	}
+	bp := NewBlueprint().WithServices(
+		NewWebService("api", RuntimeNode).WithPlan(PlanStandard).WithAutoScaling(1, 3),
+		NewBackgroundWorker("worker", RuntimeNode).WithPlan(PlanStarter),
+		NewStaticSite("frontend").WithPublishPath("./dist"),
+	)
+
+	total, perResource, err := bp.EstimateCost(prices)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]float64{"api": 75, "worker": 7}
+	if !reflect.DeepEqual(perResource, expected) {
+		t.Errorf("expected %v, got %v", expected, perResource)
+	}
+	if total != 82 {
+		t.Errorf("expected total 82, got %v", total)
+	}
+
+	bp.Databases = []Database{*NewDatabase("main-db").WithPlan(PlanBasic1GB)}
+	if _, _, err := bp.EstimateCost(prices); err == nil || !strings.Contains(err.Error(), "basic-1gb") {
+		t.Errorf("expected an error for the unpriced database plan, got %v", err)
+	}
+
+	// Unset plans are priced at Render's defaults
+	unset := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithScaling(2),
+			NewKeyValueService("cache"),
+			NewStaticSite("frontend").WithPublishPath("./dist"),
+		).
+		WithDatabases(NewDatabase("main-db"))
+	prices[PlanBasic256MB] = 6
+
+	total, perResource, err = unset.EstimateCost(prices)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = map[string]float64{"api": 14, "cache": 7, "main-db": 6}
+	if !reflect.DeepEqual(perResource, expected) {
+		t.Errorf("expected %v, got %v", expected, perResource)
+	}
+	if total != 27 {
+		t.Errorf("expected total 27, got %v", total)
+	}
+}
+
+func TestPublicSurface(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithDomains("api.example.com"),
+			NewBackgroundWorker("worker", RuntimeNode),
+			NewKeyValueService("cache").WithPublicAccess(),
+			NewKeyValueService("sessions").WithIPAllowList(IPAllow{Source: "::/0", Description: stringPtr("everywhere")}),
+			NewKeyValueService("queue").WithIPAllowList(IPAllow{Source: "10.0.0.0/8", Description: stringPtr("vpc")}),
+			NewKeyValueService("defaults"),
+		).
+		WithDatabases(
+			NewDatabase("main-db").WithPrivateAccess(),
+			NewDatabase("analytics-db"),
+		)
+
+	expected := PublicSurfaceReport{
+		WebServices: []PublicWebService{{Name: "api", Domains: []string{"api.example.com"}}},
+		DataStores: []PublicDataStore{
+			{Name: "cache", Type: "keyvalue"},
+			{Name: "sessions", Type: "keyvalue"},
+			{Name: "defaults", Type: "keyvalue"},
+			{Name: "analytics-db", Type: "database"},
+		},
+	}
+	if got := bp.PublicSurface(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
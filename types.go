@@ -1,5 +1,7 @@
 package render
 
+import "fmt"
+
 // String enum types
 type ServiceType string
 type Runtime string
@@ -57,6 +59,36 @@ const (
 	PlanFree Plan = "free"
 )
 
+// ParsePlan converts a string to a known Plan
+func ParsePlan(s string) (Plan, error) {
+	switch plan := Plan(s); plan {
+	case PlanFree, PlanStarter, PlanStandard, PlanStandard2x, PlanStandard4x,
+		PlanPro, PlanPro2x, PlanPro4x, PlanProMax,
+		PlanBasic256MB, PlanBasic1GB, PlanBasic4GB, PlanPro8GB, PlanPro16GB:
+		return plan, nil
+	}
+	return "", fmt.Errorf("unsupported plan %q", s)
+}
+
+// IsServicePlan reports whether the plan is an instance type for services
+func (p Plan) IsServicePlan() bool {
+	switch p {
+	case PlanFree, PlanStarter, PlanStandard, PlanStandard2x, PlanStandard4x,
+		PlanPro, PlanPro2x, PlanPro4x, PlanProMax:
+		return true
+	}
+	return false
+}
+
+// IsDatabasePlan reports whether the plan is an instance type for Postgres databases
+func (p Plan) IsDatabasePlan() bool {
+	switch p {
+	case PlanFree, PlanBasic256MB, PlanBasic1GB, PlanBasic4GB, PlanPro8GB, PlanPro16GB:
+		return true
+	}
+	return false
+}
+
 // Regions
 const (
 	RegionOregon    Region = "oregon"
@@ -65,6 +97,24 @@ const (
 	RegionSingapore Region = "singapore"
 )
 
+// IsValid reports whether the region is one of the known Render regions
+func (r Region) IsValid() bool {
+	switch r {
+	case RegionOregon, RegionVirginia, RegionFrankfurt, RegionSingapore:
+		return true
+	}
+	return false
+}
+
+// ParseRegion converts a string to a known Region
+func ParseRegion(s string) (Region, error) {
+	region := Region(s)
+	if !region.IsValid() {
+		return "", fmt.Errorf("unsupported region %q", s)
+	}
+	return region, nil
+}
+
 // Preview Generation
 const (
 	PreviewGenerationAutomatic PreviewGeneration = "automatic"
@@ -87,6 +137,16 @@ const (
 	MaxMemoryPolicyNoEviction     MaxMemoryPolicy = "noeviction"
 )
 
+// IsValid reports whether the policy is one of the known eviction policies
+func (p MaxMemoryPolicy) IsValid() bool {
+	switch p {
+	case MaxMemoryPolicyAllKeysLRU, MaxMemoryPolicyAllKeysRandom, MaxMemoryPolicyVolatileLRU,
+		MaxMemoryPolicyVolatileRandom, MaxMemoryPolicyVolatileTTL, MaxMemoryPolicyNoEviction:
+		return true
+	}
+	return false
+}
+
 // Database Properties
 const (
 	DatabasePropertyConnectionString         DatabaseProperty = "connectionString"
@@ -121,6 +181,9 @@ type Blueprint struct {
 	EnvVarGroups            []EnvVarGroup `yaml:"envVarGroups,omitempty"`
 	Previews                *Previews     `yaml:"previews,omitempty"`
 	PreviewsExpireAfterDays *int          `yaml:"previewsExpireAfterDays,omitempty"`
+
+	// defaults fill unset service fields in WithServices, see NewBlueprintWithDefaults
+	defaults *Defaults
 }
 
 // Service types
@@ -193,6 +256,9 @@ type Service struct {
 	
 	// Health check
 	HealthCheckPath *string `yaml:"healthCheckPath,omitempty"`
+
+	// Selection tags, never written to YAML
+	Tags []string `yaml:"-"`
 }
 
 // Database configuration
@@ -218,6 +284,9 @@ type Database struct {
 	// High availability and replicas
 	ReadReplicas     []ReadReplica     `yaml:"readReplicas,omitempty"`
 	HighAvailability *HighAvailability `yaml:"highAvailability,omitempty"`
+
+	// errs holds problems recorded while building, see Errors
+	errs []error
 }
 
 // Environment variable configuration
@@ -291,6 +360,9 @@ type Disk struct {
 	SizeGB    *int   `yaml:"sizeGB,omitempty"`
 }
 
+// MaxDiskSizeGB is the hard ceiling on a service disk size
+const MaxDiskSizeGB = 1000
+
 // HTTP headers for static sites
 type Header struct {
 	Path  string `yaml:"path"`
@@ -314,6 +386,9 @@ type IPAllow struct {
 // Read replica configuration
 type ReadReplica struct {
 	Name string `yaml:"name"`
+	// Region is library-only placement metadata checked by validation
+	// The Render schema has no replica region, so it is never written or read
+	Region *Region `yaml:"-"`
 }
 
 // High availability configuration
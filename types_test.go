@@ -0,0 +1,25 @@
+package render
+
+import "testing"
+
+func TestParseRegion(t *testing.T) {
+	region, err := ParseRegion("oregon")
+	if err != nil || region != RegionOregon {
+		t.Errorf("expected %q, got %q (%v)", RegionOregon, region, err)
+	}
+
+	if _, err := ParseRegion("us-west"); err == nil {
+		t.Errorf("expected an error for an unsupported region")
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan("pro-8gb")
+	if err != nil || plan != PlanPro8GB {
+		t.Errorf("expected %q, got %q (%v)", PlanPro8GB, plan, err)
+	}
+
+	if _, err := ParsePlan("enterprise"); err == nil {
+		t.Errorf("expected an error for an unsupported plan")
+	}
+}
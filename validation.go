@@ -0,0 +1,1197 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Severity indicates how serious a validation issue is
+type Severity string
+
+const (
+	// SeverityError marks an issue that makes the blueprint invalid
+	SeverityError Severity = "error"
+	// SeverityWarning marks an advisory issue that does not block writing
+	SeverityWarning Severity = "warning"
+)
+
+// Validation issue codes
+const (
+	CodeNilBlueprint          = "nil-blueprint"
+	CodeDuplicateServiceName  = "duplicate-service-name"
+	CodeDuplicateDatabaseName = "duplicate-database-name"
+	CodeDuplicateEnvGroupName = "duplicate-env-group-name"
+	CodeMissingServiceName    = "missing-service-name"
+	CodeMissingServiceType    = "missing-service-type"
+	CodeMissingServiceRuntime = "missing-service-runtime"
+	CodeMissingDatabaseName   = "missing-database-name"
+	CodeMissingEnvGroupName   = "missing-env-group-name"
+	CodeAutoDeployWithoutRepo = "auto-deploy-without-repo"
+	CodeInvalidDatabaseName   = "invalid-database-name"
+	CodeInvalidDatabaseUser   = "invalid-database-user"
+	CodeBareWebService        = "bare-web-service"
+	CodeInvalidPreviewPlan    = "invalid-preview-plan"
+	CodeInvalidReplicaRegion  = "invalid-replica-region"
+	CodeReplicaSameRegion     = "replica-same-region"
+	CodeTooManyRoutes         = "too-many-routes"
+	CodeTooManyHeaders        = "too-many-headers"
+	CodeDuplicateEnvVarKey    = "duplicate-env-var-key"
+	CodeUndefinedGroupKey     = "undefined-group-key"
+	CodeInvalidRegion         = "invalid-region"
+	CodeAllowListOverridden   = "allow-list-overridden"
+	CodeAutoscalingNoTarget   = "autoscaling-no-target"
+	CodePreviewsDisabled      = "previews-disabled"
+	CodeInvalidDockerContext  = "invalid-docker-context"
+	CodeDockerCommandRuntime  = "docker-command-runtime"
+	CodeReservedEnvVar        = "reserved-env-var"
+	CodeMisplacedMemoryPolicy = "misplaced-maxmemory-policy"
+	CodeInvalidMemoryPolicy   = "invalid-maxmemory-policy"
+	CodeFrequentCron          = "frequent-cron"
+	CodeMissingRegion         = "missing-region"
+	CodeImplicitAllowList     = "implicit-allow-list"
+	CodeInvalidImageURL       = "invalid-image-url"
+	CodeDiskNotSupported      = "disk-not-supported"
+	CodeDiskSizeNotSupported  = "disk-size-not-supported"
+	CodeTooManyReplicas       = "too-many-replicas"
+	CodeDuplicateDomain       = "duplicate-domain"
+	CodeWildcardDomainOverlap = "wildcard-domain-overlap"
+	CodeScalingFixedCount     = "scaling-fixed-count"
+	CodeServiceTypeMismatch   = "service-type-mismatch"
+	CodeNoServices            = "no-services"
+	CodeInvalidBuildFilter    = "invalid-build-filter"
+	CodeEmptyEnvGroup         = "empty-env-group"
+	CodeMutableImageTag       = "mutable-image-tag"
+	CodeMisplacedDomains      = "misplaced-domains"
+	CodeNameCaseCollision     = "name-case-collision"
+	CodeDeprecatedType        = "deprecated-service-type"
+	CodeHANotSupported        = "high-availability-not-supported"
+	CodeEnvValueControlChars  = "env-value-control-chars"
+	CodeDefaultDomainConflict = "default-domain-conflict"
+	CodeUnknownReference      = "unknown-reference"
+)
+
+// postgresIdentifierPattern matches unquoted PostgreSQL identifiers (at most 63 bytes)
+var postgresIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]{0,62}$`)
+
+// imageReferencePattern matches OCI image references: [registry/]repo[:tag][@digest]
+var imageReferencePattern = regexp.MustCompile(`^` +
+	`(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[\w][\w.-]{0,127})?` +
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9A-Fa-f]{32,})?$`)
+
+// imageDigestPattern matches a sha256 image digest
+var imageDigestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// reservedEnvVarNames are env vars that Render sets on every service
+var reservedEnvVarNames = map[string]bool{
+	"PORT":            true,
+	"RENDER":          true,
+	"IS_PULL_REQUEST": true,
+}
+
+// reservedEnvVarPrefix marks the namespace Render uses for injected env vars
+const reservedEnvVarPrefix = "RENDER_"
+
+// Default limits used when ValidationOptions leaves them unset
+const (
+	DefaultMaxStaticRoutes  = 100
+	DefaultMaxStaticHeaders = 100
+	DefaultMinCronInterval  = time.Minute
+)
+
+// ValidationOptions configures ValidateBlueprintWithOptions
+// The zero value uses the defaults
+type ValidationOptions struct {
+	// MaxRoutes is the most routes a static site may define
+	MaxRoutes int
+	// MaxHeaders is the most headers a static site may define
+	MaxHeaders int
+	// MinCronInterval flags cron schedules that run this often or more
+	MinCronInterval time.Duration
+	// SkipCodes drops issues with these codes from the result
+	SkipCodes []string
+	// CaseSensitiveNames turns off the check for names that differ only in case
+	CaseSensitiveNames bool
+	// RejectDeprecated reports the deprecated redis service type as an error
+	RejectDeprecated bool
+	// StrictReferences reports env var references to resources outside the blueprint as errors
+	StrictReferences bool
+	// Advisory adds warnings for settings that are valid but worth a second look,
+	// such as a database that leaves its access policy to Render's default
+	Advisory bool
+}
+
+// withDefaults fills unset options with their default values
+func (opts ValidationOptions) withDefaults() ValidationOptions {
+	if opts.MaxRoutes <= 0 {
+		opts.MaxRoutes = DefaultMaxStaticRoutes
+	}
+	if opts.MaxHeaders <= 0 {
+		opts.MaxHeaders = DefaultMaxStaticHeaders
+	}
+	if opts.MinCronInterval <= 0 {
+		opts.MinCronInterval = DefaultMinCronInterval
+	}
+	return opts
+}
+
+// ValidationIssue describes a single problem found while validating a blueprint
+type ValidationIssue struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Resource string   `json:"resource,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// String returns the issue message
+func (vi ValidationIssue) String() string {
+	return vi.Message
+}
+
+// newError creates an error-severity issue
+func newError(code, resource, format string, args ...interface{}) ValidationIssue {
+	return ValidationIssue{
+		Severity: SeverityError,
+		Code:     code,
+		Resource: resource,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// newWarning creates a warning-severity issue
+func newWarning(code, resource, format string, args ...interface{}) ValidationIssue {
+	return ValidationIssue{
+		Severity: SeverityWarning,
+		Code:     code,
+		Resource: resource,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// ValidateBlueprintDetailed checks a blueprint and returns structured issues
+// Errors make the blueprint invalid; warnings are advisory
+func ValidateBlueprintDetailed(bp *Blueprint) []ValidationIssue {
+	return ValidateBlueprintWithOptions(bp, ValidationOptions{})
+}
+
+// ValidateBlueprintWithOptions checks a blueprint using the given options
+func ValidateBlueprintWithOptions(bp *Blueprint, opts ValidationOptions) []ValidationIssue {
+	opts = opts.withDefaults()
+
+	if bp == nil {
+		return []ValidationIssue{newError(CodeNilBlueprint, "", "blueprint is nil")}
+	}
+
+	var issues []ValidationIssue
+	if len(bp.Services) == 0 {
+		issues = append(issues, newWarning(CodeNoServices, "", "blueprint has no services"))
+	}
+	issues = append(issues, validateUniqueNames(bp)...)
+	if !opts.CaseSensitiveNames {
+		issues = append(issues, validateNameCase(bp)...)
+	}
+	issues = append(issues, validateRequiredFields(bp)...)
+	issues = append(issues, validateServiceSettings(bp)...)
+	issues = append(issues, validateDatabaseSettings(bp)...)
+	issues = append(issues, validateDomains(bp)...)
+	issues = append(issues, validateDefaultDomains(bp)...)
+	issues = append(issues, validateRegions(bp)...)
+	issues = append(issues, validateIPAllowLists(bp)...)
+	if opts.Advisory {
+		issues = append(issues, validateImplicitAllowLists(bp)...)
+	}
+	issues = append(issues, validatePreviewSettings(bp)...)
+	issues = append(issues, validateEnvVarKeys(bp)...)
+	issues = append(issues, validateEmptyEnvGroups(bp)...)
+	issues = append(issues, validateEnvVarValues(bp)...)
+	issues = append(issues, validateGroupKeyReferences(bp)...)
+	issues = append(issues, validateServiceReferences(bp)...)
+	if opts.StrictReferences {
+		issues = append(issues, validateReferencesResolve(bp)...)
+	}
+	issues = append(issues, validateReservedEnvVars(bp)...)
+	issues = append(issues, ValidateServiceShape(bp)...)
+	issues = append(issues, ValidateRegionConsistency(bp)...)
+	issues = append(issues, validateStaticSiteLimits(bp, opts)...)
+	issues = append(issues, validateCronFrequency(bp, opts)...)
+	issues = append(issues, bp.ValidatePlanFeatures()...)
+	if opts.RejectDeprecated {
+		issues = append(issues, validateDeprecatedTypes(bp)...)
+	}
+	return skipIssues(issues, opts.SkipCodes)
+}
+
+// validateDeprecatedTypes flags services using the deprecated redis type
+func validateDeprecatedTypes(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		if service.Type == ServiceTypeRedis {
+			issues = append(issues, newError(CodeDeprecatedType, service.Name, "service %s uses deprecated type redis; use keyvalue", service.Name))
+		}
+	}
+
+	return issues
+}
+
+// skipIssues removes issues whose code is listed in codes
+func skipIssues(issues []ValidationIssue, codes []string) []ValidationIssue {
+	if len(codes) == 0 {
+		return issues
+	}
+
+	skip := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		skip[code] = true
+	}
+
+	var kept []ValidationIssue
+	for _, issue := range issues {
+		if !skip[issue.Code] {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// validateUniqueNames checks for duplicate names within each resource kind
+func validateUniqueNames(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	// Check for duplicate service names
+	serviceNames := make(map[string]bool)
+	for _, service := range bp.Services {
+		if serviceNames[service.Name] {
+			issues = append(issues, newError(CodeDuplicateServiceName, service.Name, "duplicate service name: %s", service.Name))
+		}
+		serviceNames[service.Name] = true
+	}
+
+	// Check for duplicate database names
+	dbNames := make(map[string]bool)
+	for _, db := range bp.Databases {
+		if dbNames[db.Name] {
+			issues = append(issues, newError(CodeDuplicateDatabaseName, db.Name, "duplicate database name: %s", db.Name))
+		}
+		dbNames[db.Name] = true
+	}
+
+	// Check for duplicate environment group names
+	envGroupNames := make(map[string]bool)
+	for _, group := range bp.EnvVarGroups {
+		if envGroupNames[group.Name] {
+			issues = append(issues, newError(CodeDuplicateEnvGroupName, group.Name, "duplicate environment group name: %s", group.Name))
+		}
+		envGroupNames[group.Name] = true
+	}
+
+	return issues
+}
+
+// validateNameCase checks for names within each resource kind that differ only in case
+// Exact duplicates are reported by validateUniqueNames
+func validateNameCase(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	check := func(kind ResourceKind, names []string) {
+		firstSeen := make(map[string]string)
+		for _, name := range names {
+			folded := strings.ToLower(name)
+			if first, exists := firstSeen[folded]; exists {
+				if first != name {
+					issues = append(issues, newError(CodeNameCaseCollision, name, "%s name collision (case-insensitive): %s vs %s", kind, first, name))
+				}
+				continue
+			}
+			firstSeen[folded] = name
+		}
+	}
+
+	var serviceNames, databaseNames, groupNames []string
+	for _, service := range bp.Services {
+		serviceNames = append(serviceNames, service.Name)
+	}
+	for _, db := range bp.Databases {
+		databaseNames = append(databaseNames, db.Name)
+	}
+	for _, group := range bp.EnvVarGroups {
+		groupNames = append(groupNames, group.Name)
+	}
+
+	check(ResourceKindService, serviceNames)
+	check(ResourceKindDatabase, databaseNames)
+	check(ResourceKindEnvVarGroup, groupNames)
+
+	return issues
+}
+
+// validateRequiredFields checks that every resource has its required fields
+func validateRequiredFields(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		if service.Name == "" {
+			issues = append(issues, newError(CodeMissingServiceName, "", "service missing name"))
+		}
+		if service.Type == "" {
+			issues = append(issues, newError(CodeMissingServiceType, service.Name, "service %s missing type", service.Name))
+		}
+		// Runtime required for most service types
+		if service.Runtime == nil && service.Type != ServiceTypeKeyValue && service.Type != ServiceTypeRedis {
+			issues = append(issues, newError(CodeMissingServiceRuntime, service.Name, "service %s missing runtime", service.Name))
+		}
+	}
+
+	for _, db := range bp.Databases {
+		if db.Name == "" {
+			issues = append(issues, newError(CodeMissingDatabaseName, "", "database missing name"))
+		}
+	}
+
+	for _, group := range bp.EnvVarGroups {
+		if group.Name == "" {
+			issues = append(issues, newError(CodeMissingEnvGroupName, "", "environment group missing name"))
+		}
+	}
+
+	return issues
+}
+
+// validateServiceSettings checks per-service settings for misconfigurations
+func validateServiceSettings(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		// Image-pull services have nothing to auto-deploy from without a repo
+		if service.AutoDeploy != nil && *service.AutoDeploy && service.Repo == nil && isImagePullService(service) {
+			issues = append(issues, newError(CodeAutoDeployWithoutRepo, service.Name, "service %s has autoDeploy but no repo to deploy from", service.Name))
+		}
+
+		// Preview instances use service instance types, not database plans
+		if service.PreviewPlan != nil && !service.PreviewPlan.IsServicePlan() {
+			issues = append(issues, newError(CodeInvalidPreviewPlan, service.Name, "service %s previewPlan %s is not a service plan", service.Name, *service.PreviewPlan))
+		}
+
+		// Docker settings are repo-relative and only apply to container runtimes
+		if service.DockerContext != nil && path.IsAbs(*service.DockerContext) {
+			issues = append(issues, newError(CodeInvalidDockerContext, service.Name, "service %s dockerContext %q is not a relative path", service.Name, *service.DockerContext))
+		}
+		if service.DockerCommand != nil && service.Runtime != nil && *service.Runtime != RuntimeDocker && *service.Runtime != RuntimeImage {
+			issues = append(issues, newError(CodeDockerCommandRuntime, service.Name, "service %s sets dockerCommand but runtime is %s", service.Name, *service.Runtime))
+		}
+		// Build filter globs are matched against repo-relative paths
+		if service.BuildFilter != nil {
+			for _, entry := range append(append([]string{}, service.BuildFilter.Paths...), service.BuildFilter.IgnoredPaths...) {
+				if strings.TrimSpace(entry) == "" {
+					issues = append(issues, newError(CodeInvalidBuildFilter, service.Name, "service %s buildFilter has empty path", service.Name))
+				} else if path.IsAbs(entry) {
+					issues = append(issues, newError(CodeInvalidBuildFilter, service.Name, "service %s buildFilter has absolute path %q", service.Name, entry))
+				}
+			}
+		}
+		if service.Image != nil && !imageReferencePattern.MatchString(service.Image.URL) {
+			issues = append(issues, newError(CodeInvalidImageURL, service.Name, "service %s has invalid image url %q", service.Name, service.Image.URL))
+		} else if service.Image != nil && !strings.Contains(service.Image.URL, "@") {
+			// Tags like :latest can move between deploys; only a digest is reproducible
+			issues = append(issues, newWarning(CodeMutableImageTag, service.Name, "service %s image %q uses a mutable tag instead of a digest", service.Name, service.Image.URL))
+		}
+
+		// Eviction policies only apply to key-value stores
+		if service.MaxMemoryPolicy != nil {
+			if service.Type != ServiceTypeKeyValue && service.Type != ServiceTypeRedis {
+				issues = append(issues, newError(CodeMisplacedMemoryPolicy, service.Name, "service %s sets maxmemoryPolicy but is not a keyvalue service", service.Name))
+			} else if !service.MaxMemoryPolicy.IsValid() {
+				issues = append(issues, newError(CodeInvalidMemoryPolicy, service.Name, "service %s has unsupported maxmemoryPolicy %q", service.Name, *service.MaxMemoryPolicy))
+			}
+		}
+
+		// Autoscaling needs at least one metric to trigger on
+		if scaling := service.Scaling; scaling != nil && scaling.MinInstances != nil && scaling.MaxInstances != nil &&
+			*scaling.MaxInstances > *scaling.MinInstances && scaling.TargetCPUPercent == nil && scaling.TargetMemoryPercent == nil {
+			issues = append(issues, newError(CodeAutoscalingNoTarget, service.Name, "service %s autoscaling has no CPU or memory target", service.Name))
+		}
+
+		// Targets can never act when the instance count is pinned
+		if scaling := service.Scaling; scaling != nil && scaling.MinInstances != nil && scaling.MaxInstances != nil &&
+			*scaling.MaxInstances == *scaling.MinInstances && (scaling.TargetCPUPercent != nil || scaling.TargetMemoryPercent != nil) {
+			issues = append(issues, newError(CodeScalingFixedCount, service.Name, "service %s has scaling targets but fixed instance count", service.Name))
+		}
+	}
+
+	return issues
+}
+
+// validateDatabaseSettings checks per-database settings for misconfigurations
+func validateDatabaseSettings(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, db := range bp.Databases {
+		// Names are used verbatim as PostgreSQL identifiers at provision time
+		if db.DatabaseName != nil && !postgresIdentifierPattern.MatchString(*db.DatabaseName) {
+			issues = append(issues, newError(CodeInvalidDatabaseName, db.Name, "database %s has invalid databaseName %q", db.Name, *db.DatabaseName))
+		}
+		if db.User != nil && !postgresIdentifierPattern.MatchString(*db.User) {
+			issues = append(issues, newError(CodeInvalidDatabaseUser, db.Name, "database %s has invalid user %q", db.Name, *db.User))
+		}
+
+		// Replica placement
+		for _, replica := range db.ReadReplicas {
+			if replica.Region == nil {
+				continue
+			}
+			if !replica.Region.IsValid() {
+				issues = append(issues, newError(CodeInvalidReplicaRegion, db.Name, "database %s replica %s has unsupported region %q", db.Name, replica.Name, *replica.Region))
+			} else if db.Region != nil && *replica.Region == *db.Region {
+				issues = append(issues, newWarning(CodeReplicaSameRegion, db.Name, "database %s replica %s is in the same region as primary", db.Name, replica.Name))
+			}
+		}
+	}
+
+	return issues
+}
+
+// validateDomains checks that only web services set domains and that no domain
+// is claimed by more than one service, including concrete domains covered by a
+// wildcard on another service
+func validateDomains(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		if len(service.Domains) > 0 && service.Type != ServiceTypeWeb {
+			issues = append(issues, newError(CodeMisplacedDomains, service.Name, "%s service %s must not set domains", service.Type, service.Name))
+		}
+	}
+
+	owners := make(map[string]string)
+	for _, service := range bp.Services {
+		for _, domain := range service.Domains {
+			domain = strings.ToLower(domain)
+			if owner, exists := owners[domain]; exists {
+				if owner != service.Name {
+					issues = append(issues, newError(CodeDuplicateDomain, service.Name, "domain %s on service %s is already used by service %s", domain, service.Name, owner))
+				}
+				continue
+			}
+			owners[domain] = service.Name
+		}
+	}
+
+	for _, service := range bp.Services {
+		for _, domain := range service.Domains {
+			domain = strings.ToLower(domain)
+			if strings.HasPrefix(domain, "*.") {
+				continue
+			}
+			// A wildcard covers exactly one extra label
+			dot := strings.Index(domain, ".")
+			if dot < 0 {
+				continue
+			}
+			wildcard := "*" + domain[dot:]
+			if owner, exists := owners[wildcard]; exists && owner != service.Name {
+				issues = append(issues, newError(CodeWildcardDomainOverlap, service.Name, "domain %s on service %s is covered by wildcard %s on service %s", domain, service.Name, wildcard, owner))
+			}
+		}
+	}
+
+	return issues
+}
+
+// DefaultDomainSuffix is the domain Render serves web services on by default
+const DefaultDomainSuffix = ".onrender.com"
+
+// validateDefaultDomains warns when a web service's implied <name>.onrender.com domain
+// is claimed explicitly by another service, as can happen after prefixing or merging
+func validateDefaultDomains(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	owners := make(map[string]string)
+	for _, service := range bp.Services {
+		for _, domain := range service.Domains {
+			if _, exists := owners[strings.ToLower(domain)]; !exists {
+				owners[strings.ToLower(domain)] = service.Name
+			}
+		}
+	}
+
+	for _, service := range bp.Services {
+		if service.Type != ServiceTypeWeb || service.Name == "" {
+			continue
+		}
+		defaultDomain := strings.ToLower(service.Name) + DefaultDomainSuffix
+		if owner, exists := owners[defaultDomain]; exists && owner != service.Name {
+			issues = append(issues, newWarning(CodeDefaultDomainConflict, service.Name, "service %s default domain %s conflicts with explicit domain on %s", service.Name, defaultDomain, owner))
+		}
+	}
+
+	return issues
+}
+
+// validateRegions checks that service and database regions are known Render regions
+// Regions loaded from YAML are not checked when unmarshaling
+func validateRegions(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		if service.Region == nil {
+			continue
+		}
+		if _, err := ParseRegion(string(*service.Region)); err != nil {
+			issues = append(issues, newError(CodeInvalidRegion, service.Name, "service %s has %v", service.Name, err))
+		}
+	}
+
+	for _, db := range bp.Databases {
+		if db.Region == nil {
+			continue
+		}
+		if _, err := ParseRegion(string(*db.Region)); err != nil {
+			issues = append(issues, newError(CodeInvalidRegion, db.Name, "database %s has %v", db.Name, err))
+		}
+	}
+
+	return issues
+}
+
+// validateIPAllowLists warns when a public entry makes narrower allow list entries meaningless
+func validateIPAllowLists(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		if allowListOverridden(service.IPAllowList) {
+			issues = append(issues, newWarning(CodeAllowListOverridden, service.Name, "%s ipAllowList has 0.0.0.0/0 which overrides other entries", service.Name))
+		}
+	}
+
+	for _, db := range bp.Databases {
+		if allowListOverridden(db.IPAllowList) {
+			issues = append(issues, newWarning(CodeAllowListOverridden, db.Name, "%s ipAllowList has 0.0.0.0/0 which overrides other entries", db.Name))
+		}
+	}
+
+	return issues
+}
+
+// validateImplicitAllowLists warns about databases that leave their access policy to Render
+// A nil list leaves Render's default policy in place, while an empty one denies all external access
+func validateImplicitAllowLists(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, db := range bp.Databases {
+		if db.IPAllowList == nil {
+			issues = append(issues, newWarning(CodeImplicitAllowList, db.Name, "database %s has no ipAllowList; access policy is implicit", db.Name))
+		}
+	}
+	return issues
+}
+
+// allowListOverridden reports whether an allow list mixes 0.0.0.0/0 with narrower entries
+func allowListOverridden(allowList []IPAllow) bool {
+	public, narrower := false, false
+	for _, entry := range allowList {
+		if entry.Source == "0.0.0.0/0" {
+			public = true
+		} else {
+			narrower = true
+		}
+	}
+	return public && narrower
+}
+
+// validatePreviewSettings warns about preview-only settings on resources without previews
+func validatePreviewSettings(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	blueprintGeneration := ""
+	if bp.Previews != nil {
+		blueprintGeneration = bp.Previews.Generation
+	}
+
+	for _, service := range bp.Services {
+		generation := blueprintGeneration
+		if service.Previews != nil {
+			generation = service.Previews.Generation
+		}
+		if service.PreviewPlan != nil && !previewsEnabled(generation) {
+			issues = append(issues, newWarning(CodePreviewsDisabled, service.Name, "service %s sets previewPlan but previews are disabled", service.Name))
+		}
+	}
+
+	// Databases follow the blueprint-level setting
+	if !previewsEnabled(blueprintGeneration) {
+		for _, db := range bp.Databases {
+			if db.PreviewPlan != nil {
+				issues = append(issues, newWarning(CodePreviewsDisabled, db.Name, "database %s sets previewPlan but previews are disabled", db.Name))
+			}
+			if db.PreviewDiskSizeGB != nil {
+				issues = append(issues, newWarning(CodePreviewsDisabled, db.Name, "database %s sets previewDiskSizeGB but previews are disabled", db.Name))
+			}
+		}
+	}
+
+	return issues
+}
+
+// previewsEnabled reports whether a preview generation setting creates preview environments
+// An unset generation means Render's default, which is off
+func previewsEnabled(generation string) bool {
+	switch generation {
+	case "", string(PreviewGenerationNone), "off":
+		return false
+	}
+	return true
+}
+
+// validateEnvVarKeys checks for env var keys defined more than once in a service or group
+// Render keeps the last definition and silently drops the others
+func validateEnvVarKeys(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		for _, key := range duplicateEnvVarKeys(service.EnvVars) {
+			issues = append(issues, newError(CodeDuplicateEnvVarKey, service.Name, "service %s has duplicate env var key %s", service.Name, key))
+		}
+	}
+
+	for _, group := range bp.EnvVarGroups {
+		for _, key := range duplicateEnvVarKeys(group.EnvVars) {
+			issues = append(issues, newError(CodeDuplicateEnvVarKey, group.Name, "environment group %s has duplicate env var key %s", group.Name, key))
+		}
+	}
+
+	return issues
+}
+
+// validateEmptyEnvGroups flags environment groups without any variables
+// An empty group may be a placeholder, so this is advisory only
+func validateEmptyEnvGroups(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, group := range bp.EnvVarGroups {
+		if len(group.EnvVars) == 0 {
+			issues = append(issues, newWarning(CodeEmptyEnvGroup, group.Name, "environment group %s has no variables", group.Name))
+		}
+	}
+
+	return issues
+}
+
+// validateEnvVarValues warns about literal values with newlines or other control characters
+// They survive a round trip, but are easy to mangle when the file is edited by hand
+func validateEnvVarValues(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	check := func(kind, owner string, envVars []EnvVar) {
+		for _, envVar := range envVars {
+			if envVar.Key == nil || envVar.Value == nil || !strings.ContainsFunc(*envVar.Value, unicode.IsControl) {
+				continue
+			}
+			issues = append(issues, newWarning(CodeEnvValueControlChars, owner, "%s %s env var %s contains newlines or control characters; consider base64-encoding it or using a secret", kind, owner, *envVar.Key))
+		}
+	}
+
+	for _, service := range bp.Services {
+		check("service", service.Name, service.EnvVars)
+	}
+	for _, group := range bp.EnvVarGroups {
+		check("environment group", group.Name, group.EnvVars)
+	}
+
+	return issues
+}
+
+// duplicateEnvVarKeys returns each key that appears more than once, in first-seen order
+// Keyless entries such as fromGroup inclusions are ignored
+func duplicateEnvVarKeys(envVars []EnvVar) []string {
+	var duplicates []string
+	counts := make(map[string]int)
+
+	for _, envVar := range envVars {
+		if envVar.Key == nil {
+			continue
+		}
+		counts[*envVar.Key]++
+		if counts[*envVar.Key] == 2 {
+			duplicates = append(duplicates, *envVar.Key)
+		}
+	}
+
+	return duplicates
+}
+
+// validateGroupKeyReferences checks that fromGroup entries selecting a single key
+// name a key the group defines; otherwise the entry silently resolves to nothing
+// Groups outside the blueprint cannot be checked and are skipped
+func validateGroupKeyReferences(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	groupKeys := make(map[string]map[string]bool)
+	for _, group := range bp.EnvVarGroups {
+		keys := make(map[string]bool)
+		for _, envVar := range group.EnvVars {
+			if envVar.Key != nil {
+				keys[*envVar.Key] = true
+			}
+		}
+		groupKeys[group.Name] = keys
+	}
+
+	for _, service := range bp.Services {
+		for _, envVar := range service.EnvVars {
+			if envVar.FromGroup == nil || envVar.Key == nil {
+				continue
+			}
+			keys, ok := groupKeys[*envVar.FromGroup]
+			if ok && !keys[*envVar.Key] {
+				issues = append(issues, newError(CodeUndefinedGroupKey, service.Name, "service %s references key %s not defined in group %s", service.Name, *envVar.Key, *envVar.FromGroup))
+			}
+		}
+	}
+
+	return issues
+}
+
+// validateServiceReferences checks that fromService references use the referenced service's type
+func validateServiceReferences(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	types := make(map[string]ServiceType)
+	for _, service := range bp.Services {
+		types[service.Name] = service.Type
+	}
+
+	check := func(resource string, envVars []EnvVar) {
+		for _, envVar := range envVars {
+			if envVar.FromService == nil {
+				continue
+			}
+			actual, exists := types[envVar.FromService.Name]
+			if !exists || sameServiceType(envVar.FromService.Type, actual) {
+				continue
+			}
+			key := envVar.FromService.Name
+			if envVar.Key != nil {
+				key = *envVar.Key
+			}
+			issues = append(issues, newError(CodeServiceTypeMismatch, resource, "env var %s references service %s as %s but it is %s", key, envVar.FromService.Name, envVar.FromService.Type, actual))
+		}
+	}
+
+	for _, service := range bp.Services {
+		check(service.Name, service.EnvVars)
+	}
+	for _, group := range bp.EnvVarGroups {
+		check(group.Name, group.EnvVars)
+	}
+
+	return issues
+}
+
+// validateReferencesResolve checks that fromDatabase, fromService and fromGroup
+// references name resources defined in the blueprint
+func validateReferencesResolve(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	services := make(map[string]bool)
+	for _, service := range bp.Services {
+		services[service.Name] = true
+	}
+	databases := make(map[string]bool)
+	for _, db := range bp.Databases {
+		databases[db.Name] = true
+	}
+	groups := make(map[string]bool)
+	for _, group := range bp.EnvVarGroups {
+		groups[group.Name] = true
+	}
+
+	check := func(kind, owner string, envVars []EnvVar) {
+		for _, envVar := range envVars {
+			if envVar.FromDatabase != nil && !databases[envVar.FromDatabase.Name] {
+				issues = append(issues, newError(CodeUnknownReference, owner, "%s %s references unknown database %s", kind, owner, envVar.FromDatabase.Name))
+			}
+			if envVar.FromService != nil && !services[envVar.FromService.Name] {
+				issues = append(issues, newError(CodeUnknownReference, owner, "%s %s references unknown service %s", kind, owner, envVar.FromService.Name))
+			}
+			if envVar.FromGroup != nil && !groups[*envVar.FromGroup] {
+				issues = append(issues, newError(CodeUnknownReference, owner, "%s %s references unknown environment group %s", kind, owner, *envVar.FromGroup))
+			}
+		}
+	}
+
+	for _, service := range bp.Services {
+		check("service", service.Name, service.EnvVars)
+	}
+	for _, group := range bp.EnvVarGroups {
+		check("environment group", group.Name, group.EnvVars)
+	}
+
+	return issues
+}
+
+// sameServiceType compares service types, treating the deprecated redis type as keyvalue
+func sameServiceType(a, b ServiceType) bool {
+	if a == ServiceTypeRedis {
+		a = ServiceTypeKeyValue
+	}
+	if b == ServiceTypeRedis {
+		b = ServiceTypeKeyValue
+	}
+	return a == b
+}
+
+// validateReservedEnvVars warns about env vars that shadow names Render provides
+func validateReservedEnvVars(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		for _, key := range reservedEnvVarKeys(service.EnvVars) {
+			issues = append(issues, newWarning(CodeReservedEnvVar, service.Name, "service %s overrides reserved env var %s", service.Name, key))
+		}
+	}
+
+	for _, group := range bp.EnvVarGroups {
+		for _, key := range reservedEnvVarKeys(group.EnvVars) {
+			issues = append(issues, newWarning(CodeReservedEnvVar, group.Name, "environment group %s overrides reserved env var %s", group.Name, key))
+		}
+	}
+
+	return issues
+}
+
+// reservedEnvVarKeys returns the keys that are reserved by Render
+func reservedEnvVarKeys(envVars []EnvVar) []string {
+	var keys []string
+	for _, envVar := range envVars {
+		if envVar.Key == nil {
+			continue
+		}
+		if reservedEnvVarNames[*envVar.Key] || strings.HasPrefix(*envVar.Key, reservedEnvVarPrefix) {
+			keys = append(keys, *envVar.Key)
+		}
+	}
+	return keys
+}
+
+// ValidateServiceShape warns about web services that show no sign of serving traffic
+// A web service with no domains, health check, or start command may be misconfigured
+// or better modeled as a private service
+func ValidateServiceShape(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	if bp == nil {
+		return issues
+	}
+
+	for _, service := range bp.Services {
+		if service.Type != ServiceTypeWeb || isStaticSite(service) {
+			continue
+		}
+		if len(service.Domains) == 0 && service.HealthCheckPath == nil && service.StartCommand == nil && service.DockerCommand == nil {
+			issues = append(issues, newWarning(CodeBareWebService, service.Name, "web service %s has no domains, health check, or start command; it may be misconfigured or better suited as a private service", service.Name))
+		}
+	}
+
+	return issues
+}
+
+// ValidateRegionConsistency warns about services without a region when others set one
+// Such services fall back to the account default region, which is often an oversight
+func ValidateRegionConsistency(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	if bp == nil {
+		return issues
+	}
+
+	var regions []string
+	var missing []string
+	seen := make(map[Region]bool)
+	for _, service := range bp.Services {
+		// Static sites are served from a CDN and have no region
+		if isStaticSite(service) {
+			continue
+		}
+		if service.Region == nil {
+			missing = append(missing, service.Name)
+			continue
+		}
+		if !seen[*service.Region] {
+			seen[*service.Region] = true
+			regions = append(regions, string(*service.Region))
+		}
+	}
+
+	if len(regions) == 0 {
+		return issues
+	}
+	sort.Strings(regions)
+
+	for _, name := range missing {
+		issues = append(issues, newWarning(CodeMissingRegion, name, "service %s has no region but the blueprint otherwise uses %s", name, strings.Join(regions, ", ")))
+	}
+
+	return issues
+}
+
+// validateStaticSiteLimits warns about static sites exceeding route and header limits
+// Exceeding them fails the deploy with an opaque error
+func validateStaticSiteLimits(bp *Blueprint, opts ValidationOptions) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		if !isStaticSite(service) {
+			continue
+		}
+		if len(service.Routes) > opts.MaxRoutes {
+			issues = append(issues, newWarning(CodeTooManyRoutes, service.Name, "static site %s has %d routes (max %d)", service.Name, len(service.Routes), opts.MaxRoutes))
+		}
+		if len(service.Headers) > opts.MaxHeaders {
+			issues = append(issues, newWarning(CodeTooManyHeaders, service.Name, "static site %s has %d headers (max %d)", service.Name, len(service.Headers), opts.MaxHeaders))
+		}
+	}
+
+	return issues
+}
+
+// validateCronFrequency warns about cron jobs that run often enough to be costly
+func validateCronFrequency(bp *Blueprint, opts ValidationOptions) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		if service.Type != ServiceTypeCron || service.Schedule == nil {
+			continue
+		}
+		interval, ok := cronMinInterval(*service.Schedule)
+		if !ok || interval > opts.MinCronInterval {
+			continue
+		}
+		if interval == time.Minute {
+			issues = append(issues, newWarning(CodeFrequentCron, service.Name, "cron service %s runs every minute", service.Name))
+		} else {
+			issues = append(issues, newWarning(CodeFrequentCron, service.Name, "cron service %s runs every %d minutes", service.Name, int(interval.Minutes())))
+		}
+	}
+
+	return issues
+}
+
+// planFeatures describes the capabilities of a service or database plan
+type planFeatures struct {
+	// disks reports whether services on the plan can attach a persistent disk
+	disks bool
+	// maxDiskSizeGB is the largest service disk the plan allows
+	maxDiskSizeGB int
+	// maxReadReplicas is the most read replicas a database on the plan can have
+	maxReadReplicas int
+	// customDiskSize reports whether databases on the plan can set diskSizeGB
+	customDiskSize bool
+	// highAvailability reports whether databases on the plan can enable high availability
+	highAvailability bool
+}
+
+// planFeatureTable drives the plan capability checks in ValidatePlanFeatures
+var planFeatureTable = map[Plan]planFeatures{
+	PlanFree:       {},
+	PlanStarter:    {disks: true, maxDiskSizeGB: MaxDiskSizeGB},
+	PlanStandard:   {disks: true, maxDiskSizeGB: MaxDiskSizeGB},
+	PlanStandard2x: {disks: true, maxDiskSizeGB: MaxDiskSizeGB},
+	PlanStandard4x: {disks: true, maxDiskSizeGB: MaxDiskSizeGB},
+	PlanPro:        {disks: true, maxDiskSizeGB: MaxDiskSizeGB, maxReadReplicas: 5, customDiskSize: true, highAvailability: true},
+	PlanPro2x:      {disks: true, maxDiskSizeGB: MaxDiskSizeGB, maxReadReplicas: 5, customDiskSize: true, highAvailability: true},
+	PlanPro4x:      {disks: true, maxDiskSizeGB: MaxDiskSizeGB, maxReadReplicas: 5, customDiskSize: true, highAvailability: true},
+	PlanProMax:     {disks: true, maxDiskSizeGB: MaxDiskSizeGB, maxReadReplicas: 5, customDiskSize: true, highAvailability: true},
+	PlanBasic256MB: {maxReadReplicas: 5, customDiskSize: true},
+	PlanBasic1GB:   {maxReadReplicas: 5, customDiskSize: true},
+	PlanBasic4GB:   {maxReadReplicas: 5, customDiskSize: true},
+	PlanPro8GB:     {maxReadReplicas: 5, customDiskSize: true, highAvailability: true},
+	PlanPro16GB:    {maxReadReplicas: 5, customDiskSize: true, highAvailability: true},
+}
+
+// featuresOf looks up the features of a plan, reporting false for unset or unknown plans
+func featuresOf(plan *Plan) (planFeatures, bool) {
+	if plan == nil {
+		return planFeatures{}, false
+	}
+	features, ok := planFeatureTable[*plan]
+	return features, ok
+}
+
+// ValidatePlanFeatures reports settings that the chosen plans do not support
+// It runs every plan capability check in one call: disk support, disk size, read replica
+// limits and high availability
+func (bp *Blueprint) ValidatePlanFeatures() []ValidationIssue {
+	var issues []ValidationIssue
+
+	if bp == nil {
+		return issues
+	}
+
+	issues = append(issues, validateDiskSupport(bp)...)
+	issues = append(issues, validateDiskSizes(bp)...)
+	issues = append(issues, validateReplicaLimits(bp)...)
+	issues = append(issues, validateHighAvailability(bp)...)
+	return issues
+}
+
+// validateDiskSupport warns about services with a disk on a plan that has no disk support
+func validateDiskSupport(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		features, ok := featuresOf(service.Plan)
+		if !ok || service.Disk == nil {
+			continue
+		}
+		if !features.disks {
+			issues = append(issues, newWarning(CodeDiskNotSupported, service.Name, "service %s plan %s does not support persistent disks", service.Name, *service.Plan))
+		}
+	}
+
+	return issues
+}
+
+// validateDiskSizes warns about disk sizes the plan does not allow
+func validateDiskSizes(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, service := range bp.Services {
+		features, ok := featuresOf(service.Plan)
+		if !ok || !features.disks || service.Disk == nil || service.Disk.SizeGB == nil {
+			continue
+		}
+		if *service.Disk.SizeGB > features.maxDiskSizeGB {
+			issues = append(issues, newWarning(CodeDiskSizeNotSupported, service.Name, "service %s disk %s size %dGB exceeds the %dGB maximum for plan %s", service.Name, service.Disk.Name, *service.Disk.SizeGB, features.maxDiskSizeGB, *service.Plan))
+		}
+	}
+
+	for _, db := range bp.Databases {
+		features, ok := featuresOf(db.Plan)
+		if !ok || db.DiskSizeGB == nil {
+			continue
+		}
+		if !features.customDiskSize {
+			issues = append(issues, newWarning(CodeDiskSizeNotSupported, db.Name, "database %s plan %s does not support a custom diskSizeGB", db.Name, *db.Plan))
+		}
+	}
+
+	return issues
+}
+
+// validateReplicaLimits warns about databases with more read replicas than the plan allows
+func validateReplicaLimits(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, db := range bp.Databases {
+		features, ok := featuresOf(db.Plan)
+		if !ok || len(db.ReadReplicas) <= features.maxReadReplicas {
+			continue
+		}
+		if features.maxReadReplicas == 0 {
+			issues = append(issues, newWarning(CodeTooManyReplicas, db.Name, "database %s plan %s does not support read replicas", db.Name, *db.Plan))
+		} else {
+			issues = append(issues, newWarning(CodeTooManyReplicas, db.Name, "database %s plan %s supports at most %d read replicas (has %d)", db.Name, *db.Plan, features.maxReadReplicas, len(db.ReadReplicas)))
+		}
+	}
+
+	return issues
+}
+
+// validateHighAvailability warns about databases enabling high availability on a plan without it
+func validateHighAvailability(bp *Blueprint) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, db := range bp.Databases {
+		features, ok := featuresOf(db.Plan)
+		if !ok || db.HighAvailability == nil || !db.HighAvailability.Enabled {
+			continue
+		}
+		if !features.highAvailability {
+			issues = append(issues, newWarning(CodeHANotSupported, db.Name, "database %s has high availability but plan %s does not support it", db.Name, *db.Plan))
+		}
+	}
+
+	return issues
+}
+
+// ValidateGlobalUniqueness reports names shared by more than one kind of resource
+// Render namespaces names by kind, so this check is optional and not part of ValidateBlueprint
+func ValidateGlobalUniqueness(bp *Blueprint) []string {
+	var errors []string
+
+	if bp == nil {
+		return errors
+	}
+
+	var names []string
+	kinds := make(map[string][]string)
+	addKind := func(name, kind string) {
+		existing, seen := kinds[name]
+		if !seen {
+			names = append(names, name)
+		}
+		for _, k := range existing {
+			if k == kind {
+				return
+			}
+		}
+		kinds[name] = append(existing, kind)
+	}
+
+	for _, service := range bp.Services {
+		addKind(service.Name, "a service")
+	}
+	for _, db := range bp.Databases {
+		addKind(db.Name, "a database")
+	}
+	for _, group := range bp.EnvVarGroups {
+		addKind(group.Name, "an environment group")
+	}
+
+	for _, name := range names {
+		switch used := kinds[name]; len(used) {
+		case 1:
+			continue
+		case 2:
+			errors = append(errors, fmt.Sprintf("name %q used by both %s and %s", name, used[0], used[1]))
+		default:
+			errors = append(errors, fmt.Sprintf("name %q used by %s, %s, and %s", name, used[0], used[1], used[2]))
+		}
+	}
+
+	return errors
+}
+
+// isStaticSite reports whether a generic service is a static site
+func isStaticSite(service Service) bool {
+	return service.Type == ServiceTypeWeb && service.Runtime != nil && *service.Runtime == RuntimeStatic
+}
+
+// isImagePullService reports whether a service deploys a prebuilt image rather than building from source
+func isImagePullService(service Service) bool {
+	if service.DockerfilePath != nil {
+		return false
+	}
+	return service.Image != nil || (service.Runtime != nil && *service.Runtime == RuntimeImage)
+}
+
+// LintJSON returns all validation issues as a JSON array for CI tooling
+func (bp *Blueprint) LintJSON() ([]byte, error) {
+	issues := ValidateBlueprintDetailed(bp)
+	if issues == nil {
+		issues = []ValidationIssue{}
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal validation issues to JSON: %w", err)
+	}
+
+	return data, nil
+}
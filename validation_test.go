@@ -0,0 +1,1666 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLintJSON(t *testing.T) {
+	bp := &Blueprint{
+		Services: []Service{
+			{Name: "api", Type: ServiceTypeWeb, Runtime: runtimePtr(RuntimeNode)},
+			{Name: "api", Type: ServiceTypeWorker, Runtime: runtimePtr(RuntimePython)},
+			{Name: "web", Type: ServiceTypeWeb}, // missing runtime
+		},
+	}
+
+	data, err := bp.LintJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var issues []ValidationIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		t.Fatalf("LintJSON output is not valid JSON: %v\n%s", err, data)
+	}
+
+	expected := map[string]string{
+		CodeDuplicateServiceName:  "api",
+		CodeMissingServiceRuntime: "web",
+	}
+	for code, resource := range expected {
+		issue := findIssue(issues, code)
+		if issue == nil {
+			t.Errorf("expected issue with code %q in %s", code, data)
+			continue
+		}
+		if issue.Severity != SeverityError {
+			t.Errorf("expected %q to be an error, got %q", code, issue.Severity)
+		}
+		if issue.Resource != resource {
+			t.Errorf("expected %q resource %q, got %q", code, resource, issue.Resource)
+		}
+		if issue.Message == "" {
+			t.Errorf("expected %q to carry a message", code)
+		}
+	}
+
+	// A clean blueprint produces an empty array rather than null
+	clean := NewBlueprint().WithServices(NewKeyValueService("cache"))
+	data, err = clean.LintJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(findIssuesBySeverity(decodeIssues(t, data), SeverityError)) != 0 {
+		t.Errorf("expected no errors for a clean blueprint, got %s", data)
+	}
+}
+
+func TestValidateAutoDeployWithoutRepo(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   ServiceBuilder
+		expectErr bool
+	}{
+		{
+			name: "auto-deploy on image-only service",
+			service: NewWebService("api", RuntimeImage).
+				WithDockerImage("ghcr.io/example/api:1.0").
+				WithAutoDeploy(true),
+			expectErr: true,
+		},
+		{
+			name: "auto-deploy on git service",
+			service: NewWebService("api", RuntimeNode).
+				WithGit("https://github.com/example/api", "main").
+				WithAutoDeploy(true),
+			expectErr: false,
+		},
+		{
+			name: "auto-deploy disabled on image-only service",
+			service: NewWebService("api", RuntimeImage).
+				WithDockerImage("ghcr.io/example/api:1.0").
+				WithAutoDeploy(false),
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(tt.service)
+			issue := findIssue(ValidateBlueprintDetailed(bp), CodeAutoDeployWithoutRepo)
+
+			if tt.expectErr {
+				if issue == nil {
+					t.Fatalf("expected %s issue", CodeAutoDeployWithoutRepo)
+				}
+				expected := "service api has autoDeploy but no repo to deploy from"
+				if issue.Message != expected {
+					t.Errorf("expected message %q, got %q", expected, issue.Message)
+				}
+				if issue.Severity != SeverityError {
+					t.Errorf("expected error severity, got %q", issue.Severity)
+				}
+			} else if issue != nil {
+				t.Errorf("unexpected issue: %s", issue.Message)
+			}
+		})
+	}
+}
+
+func TestValidateDatabaseIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       *Database
+		expected []string
+	}{
+		{
+			name:     "spaced database name",
+			db:       NewDatabase("main-db").WithDatabaseName("my db"),
+			expected: []string{`database main-db has invalid databaseName "my db"`},
+		},
+		{
+			name:     "user with leading digit",
+			db:       NewDatabase("main-db").WithUser("1admin"),
+			expected: []string{`database main-db has invalid user "1admin"`},
+		},
+		{
+			name:     "valid name and user",
+			db:       NewDatabase("main-db").WithDatabaseName("app_production").WithUser("app_user"),
+			expected: nil,
+		},
+		{
+			name:     "default name and user",
+			db:       NewDatabase("main-db"),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithDatabases(tt.db)
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeInvalidDatabaseName || issue.Code == CodeInvalidDatabaseUser {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateServiceShape(t *testing.T) {
+	tests := []struct {
+		name          string
+		service       ServiceBuilder
+		expectWarning bool
+	}{
+		{
+			name:          "bare web service",
+			service:       NewWebService("api", RuntimeNode),
+			expectWarning: true,
+		},
+		{
+			name: "configured web service",
+			service: NewWebService("api", RuntimeNode).
+				WithStartCommand("npm start").
+				WithHealthCheck("/health"),
+			expectWarning: false,
+		},
+		{
+			name:          "static site",
+			service:       NewStaticSite("frontend").WithPublishPath("./dist"),
+			expectWarning: false,
+		},
+		{
+			name:          "background worker",
+			service:       NewBackgroundWorker("worker", RuntimePython),
+			expectWarning: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(tt.service)
+			issues := ValidateServiceShape(bp)
+
+			if tt.expectWarning {
+				if len(issues) != 1 {
+					t.Fatalf("expected 1 issue, got %v", issues)
+				}
+				if issues[0].Severity != SeverityWarning || issues[0].Code != CodeBareWebService {
+					t.Errorf("expected %s warning, got %+v", CodeBareWebService, issues[0])
+				}
+				// Warnings are advisory and never block the default validator
+				if errors := ValidateBlueprint(bp); len(errors) != 0 {
+					t.Errorf("warning should not surface as an error: %v", errors)
+				}
+				if findIssue(ValidateBlueprintDetailed(bp), CodeBareWebService) == nil {
+					t.Errorf("expected warning in detailed validation")
+				}
+			} else if len(issues) != 0 {
+				t.Errorf("expected no issues, got %v", issues)
+			}
+		})
+	}
+}
+
+func TestValidateReplicaRegions(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           *Database
+		expectedCode string
+		expectedMsg  string
+	}{
+		{
+			name: "cross-region replica",
+			db: NewDatabase("main-db").
+				WithRegion(RegionOregon).
+				WithReadReplicaInRegion("main-db-replica", RegionFrankfurt),
+		},
+		{
+			name: "same-region replica",
+			db: NewDatabase("main-db").
+				WithRegion(RegionOregon).
+				WithReadReplicaInRegion("main-db-replica", RegionOregon),
+			expectedCode: CodeReplicaSameRegion,
+			expectedMsg:  "database main-db replica main-db-replica is in the same region as primary",
+		},
+		{
+			name: "unsupported replica region",
+			db: NewDatabase("main-db").
+				WithReadReplicaInRegion("main-db-replica", Region("us-west")),
+			expectedCode: CodeInvalidReplicaRegion,
+			expectedMsg:  `database main-db replica main-db-replica has unsupported region "us-west"`,
+		},
+		{
+			name: "replica without region",
+			db:   NewDatabase("main-db").WithRegion(RegionOregon).WithReadReplicas("main-db-replica"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replicaIssues []ValidationIssue
+			for _, issue := range ValidateBlueprintDetailed(NewBlueprint().WithDatabases(tt.db)) {
+				if issue.Code == CodeReplicaSameRegion || issue.Code == CodeInvalidReplicaRegion {
+					replicaIssues = append(replicaIssues, issue)
+				}
+			}
+
+			if tt.expectedCode == "" {
+				if len(replicaIssues) != 0 {
+					t.Errorf("expected no replica issues, got %v", replicaIssues)
+				}
+				return
+			}
+			if len(replicaIssues) != 1 {
+				t.Fatalf("expected 1 replica issue, got %v", replicaIssues)
+			}
+			if replicaIssues[0].Code != tt.expectedCode || replicaIssues[0].Message != tt.expectedMsg {
+				t.Errorf("expected %s %q, got %s %q", tt.expectedCode, tt.expectedMsg, replicaIssues[0].Code, replicaIssues[0].Message)
+			}
+		})
+	}
+
+	// Replica regions are library-only and never reach render.yaml
+	yamlStr, err := NewBlueprint().WithDatabases(NewDatabase("main-db").WithReadReplicaInRegion("main-db-replica", RegionFrankfurt)).ToYAMLString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(yamlStr, "frankfurt") {
+		t.Errorf("expected the replica region to be omitted, got:\n%s", yamlStr)
+	}
+}
+
+func TestValidateStaticSiteLimits(t *testing.T) {
+	newSite := func(routeCount int) *Blueprint {
+		site := NewStaticSite("frontend").WithPublishPath("./dist")
+		for i := 0; i < routeCount; i++ {
+			site.WithRoutes(Route{
+				Type:        string(RouteTypeRewrite),
+				Source:      fmt.Sprintf("/page-%d", i),
+				Destination: "/index.html",
+			})
+		}
+		return NewBlueprint().WithServices(site)
+	}
+
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		opts     ValidationOptions
+		expected string
+	}{
+		{
+			name:     "over the default limit",
+			bp:       newSite(150),
+			expected: "static site frontend has 150 routes (max 100)",
+		},
+		{
+			name: "within the default limit",
+			bp:   newSite(100),
+		},
+		{
+			name:     "over a configured limit",
+			bp:       newSite(20),
+			opts:     ValidationOptions{MaxRoutes: 10},
+			expected: "static site frontend has 20 routes (max 10)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := findIssue(ValidateBlueprintWithOptions(tt.bp, tt.opts), CodeTooManyRoutes)
+
+			if tt.expected == "" {
+				if issue != nil {
+					t.Errorf("unexpected issue: %s", issue.Message)
+				}
+				return
+			}
+			if issue == nil {
+				t.Fatalf("expected %s issue", CodeTooManyRoutes)
+			}
+			if issue.Message != tt.expected || issue.Severity != SeverityWarning {
+				t.Errorf("expected warning %q, got %s %q", tt.expected, issue.Severity, issue.Message)
+			}
+		})
+	}
+}
+
+func TestValidateDuplicateEnvVarKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name: "duplicate service key",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).
+					WithEnv("PORT", "8080").
+					WithEnv("PORT", "9090"),
+			),
+			expected: []string{"service api has duplicate env var key PORT"},
+		},
+		{
+			name: "duplicate group key",
+			bp: NewBlueprint().WithEnvVarGroups(
+				NewEnvVarGroup("shared").
+					WithEnvVars(Env("LOG_LEVEL", "info"), Env("LOG_LEVEL", "debug")),
+			),
+			expected: []string{"environment group shared has duplicate env var key LOG_LEVEL"},
+		},
+		{
+			name: "distinct keys and group inclusions",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).
+					WithEnv("PORT", "8080").
+					WithEnv("NODE_ENV", "production").
+					WithEnvFromGroupsOrdered("shared", "secrets"),
+			),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(tt.bp) {
+				if issue.Code == CodeDuplicateEnvVarKey {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateGlobalUniqueness(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name: "service and database clash",
+			bp: NewBlueprint().
+				WithServices(NewWebService("api", RuntimeNode)).
+				WithDatabases(NewDatabase("api")),
+			expected: []string{`name "api" used by both a service and a database`},
+		},
+		{
+			name: "clash across all kinds",
+			bp: NewBlueprint().
+				WithServices(NewWebService("app", RuntimeNode)).
+				WithDatabases(NewDatabase("app")).
+				WithEnvVarGroups(NewEnvVarGroup("app")),
+			expected: []string{`name "app" used by a service, a database, and an environment group`},
+		},
+		{
+			name: "distinct names",
+			bp: NewBlueprint().
+				WithServices(NewWebService("api", RuntimeNode)).
+				WithDatabases(NewDatabase("api-db")).
+				WithEnvVarGroups(NewEnvVarGroup("api-env")),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateGlobalUniqueness(tt.bp)
+			if !reflect.DeepEqual(errors, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, errors)
+			}
+		})
+	}
+
+	// Cross-kind clashes are not part of the default validator
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode)).
+		WithDatabases(NewDatabase("api"))
+	if errors := ValidateBlueprint(bp); len(errors) != 0 {
+		t.Errorf("expected default validation to allow cross-kind names, got %v", errors)
+	}
+}
+
+func TestValidateGroupKeyReferences(t *testing.T) {
+	newBlueprint := func(key string) *Blueprint {
+		return NewBlueprint().
+			WithServices(
+				NewWebService("api", RuntimeNode).
+					WithEnvVars(EnvVar{Key: stringPtr(key), FromGroup: stringPtr("shared")}),
+			).
+			WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("LOG_LEVEL", "info"))
+	}
+
+	issue := findIssue(ValidateBlueprintDetailed(newBlueprint("NOPE")), CodeUndefinedGroupKey)
+	if issue == nil {
+		t.Fatalf("expected %s issue", CodeUndefinedGroupKey)
+	}
+	expected := "service api references key NOPE not defined in group shared"
+	if issue.Message != expected || issue.Severity != SeverityError {
+		t.Errorf("expected error %q, got %s %q", expected, issue.Severity, issue.Message)
+	}
+
+	if issue := findIssue(ValidateBlueprintDetailed(newBlueprint("LOG_LEVEL")), CodeUndefinedGroupKey); issue != nil {
+		t.Errorf("unexpected issue: %s", issue.Message)
+	}
+
+	// Keyless inclusions pull in the whole group and are always valid
+	bp := NewBlueprint().
+		WithServices(NewWebService("api", RuntimeNode).WithEnvFromGroupsOrdered("shared")).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("LOG_LEVEL", "info"))
+	if issue := findIssue(ValidateBlueprintDetailed(bp), CodeUndefinedGroupKey); issue != nil {
+		t.Errorf("unexpected issue: %s", issue.Message)
+	}
+}
+
+func TestValidateRegions(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name: "unsupported service region",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).WithRegion(Region("us-west")),
+			),
+			expected: []string{`service api has unsupported region "us-west"`},
+		},
+		{
+			name:     "unsupported database region",
+			bp:       NewBlueprint().WithDatabases(NewDatabase("main-db").WithRegion(Region("mars"))),
+			expected: []string{`database main-db has unsupported region "mars"`},
+		},
+		{
+			name: "supported regions",
+			bp: NewBlueprint().
+				WithServices(NewWebService("api", RuntimeNode).WithRegion(RegionFrankfurt)).
+				WithDatabases(NewDatabase("main-db").WithRegion(RegionFrankfurt)),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(tt.bp) {
+				if issue.Code == CodeInvalidRegion {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateIPAllowLists(t *testing.T) {
+	office := IPAllow{Source: "203.0.113.0/24"}
+
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name: "public and restrictive key-value",
+			bp: NewBlueprint().WithServices(
+				NewKeyValueService("cache").WithIPAllowList(office).WithPublicAccess(),
+			),
+			expected: []string{"cache ipAllowList has 0.0.0.0/0 which overrides other entries"},
+		},
+		{
+			name: "public and restrictive database",
+			bp: NewBlueprint().WithDatabases(
+				NewDatabase("main-db").WithPublicAccess().WithIPAllowList(office),
+			),
+			expected: []string{"main-db ipAllowList has 0.0.0.0/0 which overrides other entries"},
+		},
+		{
+			name: "restrictive only",
+			bp: NewBlueprint().WithServices(
+				NewKeyValueService("cache").WithIPAllowList(office, IPAllow{Source: "198.51.100.7/32"}),
+			),
+			expected: nil,
+		},
+		{
+			name:     "public only",
+			bp:       NewBlueprint().WithDatabases(NewDatabase("main-db").WithPublicAccess()),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(tt.bp) {
+				if issue.Code == CodeAllowListOverridden {
+					if issue.Severity != SeverityWarning {
+						t.Errorf("expected warning severity, got %q", issue.Severity)
+					}
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateAutoscalingTargets(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   *WebService
+		expectErr bool
+	}{
+		{
+			name:      "autoscaling without a target",
+			service:   NewWebService("api", RuntimeNode).WithAutoScaling(1, 3),
+			expectErr: true,
+		},
+		{
+			name:    "autoscaling on CPU",
+			service: NewWebService("api", RuntimeNode).WithAutoScaling(1, 3, 70),
+		},
+		{
+			name:    "autoscaling on CPU and memory",
+			service: NewWebService("api", RuntimeNode).WithAutoScalingTargets(1, 3, 70, 80),
+		},
+		{
+			name:    "fixed range without a target",
+			service: NewWebService("api", RuntimeNode).WithAutoScaling(2, 2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateBlueprint(NewBlueprint().WithServices(tt.service))
+			expected := "service api autoscaling has no CPU or memory target"
+
+			found := false
+			for _, err := range errors {
+				if err == expected {
+					found = true
+				}
+			}
+			if found != tt.expectErr {
+				t.Errorf("expected error %v, got %v", tt.expectErr, errors)
+			}
+		})
+	}
+}
+
+func TestValidateScalingFixedCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   *WebService
+		expectErr bool
+	}{
+		{
+			name:      "fixed count with a CPU target",
+			service:   NewWebService("api", RuntimeNode).WithAutoScaling(2, 2, 70),
+			expectErr: true,
+		},
+		{
+			name:      "fixed count with a memory target",
+			service:   NewWebService("api", RuntimeNode).WithAutoScalingTargets(3, 3, 0, 80),
+			expectErr: true,
+		},
+		{
+			name:    "proper autoscaling range",
+			service: NewWebService("api", RuntimeNode).WithAutoScaling(1, 3, 70),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateBlueprint(NewBlueprint().WithServices(tt.service))
+			expected := "service api has scaling targets but fixed instance count"
+
+			found := false
+			for _, err := range errors {
+				if err == expected {
+					found = true
+				}
+			}
+			if found != tt.expectErr {
+				t.Errorf("expected error %v, got %v", tt.expectErr, errors)
+			}
+		})
+	}
+}
+
+func TestValidatePreviewSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name: "service preview plan with previews disabled",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).WithPreviewEnvironment(PreviewGenerationNone, PlanStarter),
+			),
+			expected: []string{"service api sets previewPlan but previews are disabled"},
+		},
+		{
+			name: "database preview settings with blueprint previews disabled",
+			bp: NewBlueprint().
+				WithDatabases(NewDatabase("main-db").WithPreviewPlan(PlanBasic256MB).WithPreviewDiskSize(5)).
+				WithPreviews(PreviewGenerationNone),
+			expected: []string{
+				"database main-db sets previewPlan but previews are disabled",
+				"database main-db sets previewDiskSizeGB but previews are disabled",
+			},
+		},
+		{
+			name: "service previews enabled",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).WithPreviewEnvironment(PreviewGenerationAutomatic, PlanStarter),
+			),
+			expected: nil,
+		},
+		{
+			name: "database previews enabled at blueprint level",
+			bp: NewBlueprint().
+				WithDatabases(NewDatabase("main-db").WithPreviewPlan(PlanBasic256MB)).
+				WithPreviews(PreviewGenerationAutomatic),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(tt.bp) {
+				if issue.Code == CodePreviewsDisabled {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateDockerSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  Service
+		expected []string
+	}{
+		{
+			name: "dockerCommand on a node service",
+			service: Service{
+				Name: "api", Type: ServiceTypeWeb, Runtime: runtimePtr(RuntimeNode),
+				DockerCommand: stringPtr("./start.sh"),
+			},
+			expected: []string{"service api sets dockerCommand but runtime is node"},
+		},
+		{
+			name: "absolute docker context",
+			service: Service{
+				Name: "api", Type: ServiceTypeWeb, Runtime: runtimePtr(RuntimeDocker),
+				DockerContext: stringPtr("/srv/app"),
+			},
+			expected: []string{`service api dockerContext "/srv/app" is not a relative path`},
+		},
+		{
+			name: "valid docker service",
+			service: Service{
+				Name: "api", Type: ServiceTypeWeb, Runtime: runtimePtr(RuntimeDocker),
+				DockerCommand: stringPtr("./start.sh"), DockerContext: stringPtr("./backend"),
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := &Blueprint{Services: []Service{tt.service}}
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeDockerCommandRuntime || issue.Code == CodeInvalidDockerContext {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateReservedEnvVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name:     "reserved key",
+			bp:       NewBlueprint().WithServices(NewWebService("api", RuntimeNode).WithEnv("PORT", "3000")),
+			expected: []string{"service api overrides reserved env var PORT"},
+		},
+		{
+			name: "render-injected prefix in a group",
+			bp: NewBlueprint().WithEnvVarGroups(
+				NewEnvVarGroup("shared").WithEnv("RENDER_EXTERNAL_URL", "https://example.com"),
+			),
+			expected: []string{"environment group shared overrides reserved env var RENDER_EXTERNAL_URL"},
+		},
+		{
+			name:     "normal key",
+			bp:       NewBlueprint().WithServices(NewWebService("api", RuntimeNode).WithEnv("APP_PORT", "3000")),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(tt.bp) {
+				if issue.Code == CodeReservedEnvVar {
+					if issue.Severity != SeverityWarning {
+						t.Errorf("expected warning severity, got %q", issue.Severity)
+					}
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateMaxMemoryPolicy(t *testing.T) {
+	policy := func(p MaxMemoryPolicy) *MaxMemoryPolicy { return &p }
+
+	tests := []struct {
+		name     string
+		service  Service
+		expected []string
+	}{
+		{
+			name: "policy on a web service",
+			service: Service{
+				Name: "api", Type: ServiceTypeWeb, Runtime: runtimePtr(RuntimeNode),
+				MaxMemoryPolicy: policy(MaxMemoryPolicyAllKeysLRU),
+			},
+			expected: []string{"service api sets maxmemoryPolicy but is not a keyvalue service"},
+		},
+		{
+			name: "invalid policy value",
+			service: Service{
+				Name: "cache", Type: ServiceTypeKeyValue,
+				MaxMemoryPolicy: policy("evict-everything"),
+			},
+			expected: []string{`service cache has unsupported maxmemoryPolicy "evict-everything"`},
+		},
+		{
+			name:     "valid policy on a key-value service",
+			service:  *NewKeyValueService("cache").WithMaxMemoryPolicy(MaxMemoryPolicyNoEviction).ToService(),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := &Blueprint{Services: []Service{tt.service}}
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeMisplacedMemoryPolicy || issue.Code == CodeInvalidMemoryPolicy {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateCronFrequency(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		opts     ValidationOptions
+		expected []string
+	}{
+		{
+			name:     "every minute",
+			schedule: "* * * * *",
+			expected: []string{"cron service cleanup runs every minute"},
+		},
+		{
+			name:     "daily",
+			schedule: "0 3 * * *",
+			expected: nil,
+		},
+		{
+			name:     "every five minutes under the default threshold",
+			schedule: "*/5 * * * *",
+			expected: nil,
+		},
+		{
+			name:     "every five minutes with a higher threshold",
+			schedule: "*/5 * * * *",
+			opts:     ValidationOptions{MinCronInterval: 10 * time.Minute},
+			expected: []string{"cron service cleanup runs every 5 minutes"},
+		},
+		{
+			name:     "consecutive minutes in a list",
+			schedule: "0,1 12 * * 1",
+			expected: []string{"cron service cleanup runs every minute"},
+		},
+		{
+			name:     "unparseable schedule is left to other checks",
+			schedule: "@hourly",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(NewCronJob("cleanup", RuntimeNode, tt.schedule))
+
+			var messages []string
+			for _, issue := range ValidateBlueprintWithOptions(bp, tt.opts) {
+				if issue.Code == CodeFrequentCron {
+					if issue.Severity != SeverityWarning {
+						t.Errorf("expected a warning, got %s", issue.Severity)
+					}
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateRegionConsistency(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name: "mixed blueprint",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).WithRegion(RegionOregon),
+				NewBackgroundWorker("worker", RuntimeNode),
+				NewKeyValueService("cache").WithRegion(RegionOregon),
+				NewStaticSite("frontend").WithPublishPath("./dist"),
+			),
+			expected: []string{"service worker has no region but the blueprint otherwise uses oregon"},
+		},
+		{
+			name: "uniformly regional blueprint",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).WithRegion(RegionOregon),
+				NewBackgroundWorker("worker", RuntimeNode).WithRegion(RegionOregon),
+			),
+			expected: nil,
+		},
+		{
+			name: "no regions at all",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode),
+				NewBackgroundWorker("worker", RuntimeNode),
+			),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateRegionConsistency(tt.bp) {
+				if issue.Severity != SeverityWarning {
+					t.Errorf("expected a warning, got %s", issue.Severity)
+				}
+				messages = append(messages, issue.Message)
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateImplicitAllowList(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       *Database
+		expected []string
+	}{
+		{
+			name:     "nil allow list",
+			db:       NewDatabase("main-db"),
+			expected: []string{"database main-db has no ipAllowList; access policy is implicit"},
+		},
+		{
+			name:     "empty allow list",
+			db:       NewDatabase("main-db").WithPrivateAccess(),
+			expected: nil,
+		},
+		{
+			name:     "populated allow list",
+			db:       NewDatabase("main-db").WithIPAllowList(IPAllow{Source: "203.0.113.0/24"}),
+			expected: nil,
+		},
+	}
+
+	advisory := ValidationOptions{Advisory: true}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintWithOptions(NewBlueprint().WithDatabases(tt.db), advisory) {
+				if issue.Code == CodeImplicitAllowList {
+					if issue.Severity != SeverityWarning {
+						t.Errorf("expected warning severity, got %q", issue.Severity)
+					}
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+
+			// Writing and reloading must not change the outcome
+			yamlStr, err := NewBlueprint().WithDatabases(tt.db).ToYAMLString()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			reloaded, err := LoadFromString(yamlStr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var reloadedMessages []string
+			for _, issue := range ValidateBlueprintWithOptions(reloaded, advisory) {
+				if issue.Code == CodeImplicitAllowList {
+					reloadedMessages = append(reloadedMessages, issue.Message)
+				}
+			}
+			if !reflect.DeepEqual(reloadedMessages, tt.expected) {
+				t.Errorf("expected %v after a round trip, got %v from:\n%s", tt.expected, reloadedMessages, yamlStr)
+			}
+		})
+	}
+
+	// An explicit empty list survives a YAML round trip
+	var loaded Blueprint
+	if err := yaml.Unmarshal([]byte("databases:\n  - name: main-db\n    ipAllowList: []\n"), &loaded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issue := findIssue(ValidateBlueprintWithOptions(&loaded, advisory), CodeImplicitAllowList); issue != nil {
+		t.Errorf("expected no implicit allow list warning for an explicit empty list, got %q", issue.Message)
+	}
+
+	// The warning is advisory, so default validation and LintJSON leave it out
+	implicit := NewBlueprint().WithDatabases(NewDatabase("main-db"))
+	if issue := findIssue(ValidateBlueprintDetailed(implicit), CodeImplicitAllowList); issue != nil {
+		t.Errorf("expected no implicit allow list warning by default, got %q", issue.Message)
+	}
+	lint, err := implicit.LintJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(lint), CodeImplicitAllowList) {
+		t.Errorf("expected LintJSON to leave out the advisory warning, got %s", lint)
+	}
+}
+
+func TestValidateImageURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected []string
+	}{
+		{
+			name:     "malformed reference",
+			url:      "myimage::latest",
+			expected: []string{`service api has invalid image url "myimage::latest"`},
+		},
+		{
+			name:     "uppercase repository",
+			url:      "MyImage:latest",
+			expected: []string{`service api has invalid image url "MyImage:latest"`},
+		},
+		{
+			name:     "digest reference",
+			url:      "ghcr.io/example/api@sha256:" + strings.Repeat("a1", 32),
+			expected: nil,
+		},
+		{
+			name:     "tagged reference",
+			url:      "registry.example.com:5000/team/api:v1.2.3",
+			expected: nil,
+		},
+		{
+			name:     "bare repository",
+			url:      "nginx",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(NewWebService("api", RuntimeImage).WithDockerImage(tt.url))
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeInvalidImageURL {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidatePlanFeatures(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(
+			NewWebService("api", RuntimeNode).WithPlan(PlanFree).WithDisk("data", "/var/data", 10),
+			NewWebService("uploads", RuntimeNode).WithPlan(PlanStandard).WithDiskSpec(Disk{Name: "files", MountPath: "/files", SizeGB: intPtr(2000)}),
+			NewWebService("ok", RuntimeNode).WithPlan(PlanStandard).WithDisk("data", "/var/data", 10),
+		).
+		WithDatabases(
+			NewDatabase("free-db").WithPlan(PlanFree).WithDiskSize(5).WithReadReplicas("free-db-replica"),
+			NewDatabase("main-db").WithPlan(PlanPro8GB).WithDiskSize(100).WithReadReplicas("r1", "r2", "r3", "r4", "r5", "r6"),
+		)
+
+	issues := bp.ValidatePlanFeatures()
+
+	expected := map[string][]string{
+		CodeDiskNotSupported: {"service api plan free does not support persistent disks"},
+		CodeDiskSizeNotSupported: {
+			"service uploads disk files size 2000GB exceeds the 1000GB maximum for plan standard",
+			"database free-db plan free does not support a custom diskSizeGB",
+		},
+		CodeTooManyReplicas: {
+			"database free-db plan free does not support read replicas",
+			"database main-db plan pro-8gb supports at most 5 read replicas (has 6)",
+		},
+	}
+
+	got := make(map[string][]string)
+	for _, issue := range issues {
+		if issue.Severity != SeverityWarning {
+			t.Errorf("expected %s to be a warning, got %s", issue.Code, issue.Severity)
+		}
+		got[issue.Code] = append(got[issue.Code], issue.Message)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// The aggregate is part of the default validation
+	for code := range expected {
+		if findIssue(ValidateBlueprintDetailed(bp), code) == nil {
+			t.Errorf("expected ValidateBlueprintDetailed to include %s", code)
+		}
+	}
+}
+
+func TestValidateDomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []ServiceBuilder
+		expected []string
+	}{
+		{
+			name: "wildcard covers concrete domain",
+			services: []ServiceBuilder{
+				NewWebService("web", RuntimeNode).WithDomains("*.example.com"),
+				NewWebService("api", RuntimeNode).WithDomains("api.example.com"),
+			},
+			expected: []string{"domain api.example.com on service api is covered by wildcard *.example.com on service web"},
+		},
+		{
+			name: "exact duplicate",
+			services: []ServiceBuilder{
+				NewWebService("web", RuntimeNode).WithDomains("example.com"),
+				NewWebService("api", RuntimeNode).WithDomains("Example.com"),
+			},
+			expected: []string{"domain example.com on service api is already used by service web"},
+		},
+		{
+			name: "wildcard does not cover the apex or deeper subdomains",
+			services: []ServiceBuilder{
+				NewWebService("web", RuntimeNode).WithDomains("*.example.com"),
+				NewWebService("api", RuntimeNode).WithDomains("example.com", "v1.api.example.com"),
+			},
+			expected: nil,
+		},
+		{
+			name: "non-overlapping domains",
+			services: []ServiceBuilder{
+				NewWebService("web", RuntimeNode).WithDomains("www.example.com", "*.example.org"),
+				NewWebService("api", RuntimeNode).WithDomains("api.example.com"),
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(tt.services...)
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeDuplicateDomain || issue.Code == CodeWildcardDomainOverlap {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateServiceReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      EnvVar
+		expected []string
+	}{
+		{
+			name:     "type mismatch",
+			ref:      EnvFromService("CACHE_URL", "cache", ServiceTypeWeb, ServicePropertyConnectionString),
+			expected: []string{"env var CACHE_URL references service cache as web but it is keyvalue"},
+		},
+		{
+			name:     "matching type",
+			ref:      EnvFromService("CACHE_URL", "cache", ServiceTypeKeyValue, ServicePropertyConnectionString),
+			expected: nil,
+		},
+		{
+			name:     "deprecated redis type",
+			ref:      EnvFromService("CACHE_URL", "cache", ServiceTypeRedis, ServicePropertyConnectionString),
+			expected: nil,
+		},
+		{
+			name:     "external service",
+			ref:      EnvFromService("AUTH_HOST", "auth", ServiceTypeWeb, ServicePropertyHost),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).WithEnvVars(tt.ref),
+				NewKeyValueService("cache"),
+			)
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeServiceTypeMismatch {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateNoServices(t *testing.T) {
+	t.Run("empty blueprint", func(t *testing.T) {
+		issue := findIssue(ValidateBlueprintDetailed(NewBlueprint()), CodeNoServices)
+		if issue == nil {
+			t.Fatal("expected a no-services issue")
+		}
+		if issue.Severity != SeverityWarning || issue.Message != "blueprint has no services" {
+			t.Errorf("expected warning %q, got %s %q", "blueprint has no services", issue.Severity, issue.Message)
+		}
+		if errors := ValidateBlueprint(NewBlueprint()); len(errors) != 0 {
+			t.Errorf("expected no errors for an empty blueprint, got %v", errors)
+		}
+	})
+
+	t.Run("databases-only blueprint can skip the warning", func(t *testing.T) {
+		bp := NewBlueprint().WithDatabases(NewDatabase("main-db").WithPrivateAccess())
+
+		if findIssue(ValidateBlueprintDetailed(bp), CodeNoServices) == nil {
+			t.Error("expected a no-services issue by default")
+		}
+
+		issues := ValidateBlueprintWithOptions(bp, ValidationOptions{SkipCodes: []string{CodeNoServices}})
+		if len(issues) != 0 {
+			t.Errorf("expected no issues when skipping %s, got %+v", CodeNoServices, issues)
+		}
+	})
+
+	t.Run("blueprint with services", func(t *testing.T) {
+		bp := NewBlueprint().WithServices(NewBackgroundWorker("worker", RuntimeNode))
+		if findIssue(ValidateBlueprintDetailed(bp), CodeNoServices) != nil {
+			t.Error("expected no no-services issue")
+		}
+	})
+}
+
+func TestValidateBuildFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   BuildFilter
+		expected []string
+	}{
+		{
+			name:     "absolute path",
+			filter:   BuildFilter{Paths: []string{"/src"}},
+			expected: []string{`service api buildFilter has absolute path "/src"`},
+		},
+		{
+			name:     "empty ignored path",
+			filter:   BuildFilter{Paths: []string{"src/**"}, IgnoredPaths: []string{""}},
+			expected: []string{"service api buildFilter has empty path"},
+		},
+		{
+			name:     "relative globs",
+			filter:   BuildFilter{Paths: []string{"src/**", "go.mod"}, IgnoredPaths: []string{"**/*_test.go"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := *NewWebService("api", RuntimeNode).WithStartCommand("npm start").ToService()
+			service.BuildFilter = &tt.filter
+			bp := &Blueprint{Services: []Service{service}}
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeInvalidBuildFilter {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateEmptyEnvGroups(t *testing.T) {
+	t.Run("empty group", func(t *testing.T) {
+		bp := NewBlueprint().WithEnvVarGroups(NewEnvVarGroup("shared"))
+
+		issue := findIssue(ValidateBlueprintDetailed(bp), CodeEmptyEnvGroup)
+		if issue == nil {
+			t.Fatal("expected an empty-env-group issue")
+		}
+		if issue.Severity != SeverityWarning || issue.Message != "environment group shared has no variables" {
+			t.Errorf("expected warning %q, got %s %q", "environment group shared has no variables", issue.Severity, issue.Message)
+		}
+		if errors := ValidateBlueprint(bp); len(errors) != 0 {
+			t.Errorf("expected no errors for an empty group, got %v", errors)
+		}
+	})
+
+	t.Run("populated group", func(t *testing.T) {
+		bp := NewBlueprint().WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("LOG_LEVEL", "info"))
+		if findIssue(ValidateBlueprintDetailed(bp), CodeEmptyEnvGroup) != nil {
+			t.Error("expected no empty-env-group issue")
+		}
+	})
+}
+
+func TestValidateMutableImageTag(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name        string
+		service     *WebService
+		expectIssue bool
+	}{
+		{
+			name:        "digest-pinned image",
+			service:     NewWebService("api", RuntimeImage).WithDockerImageDigest("ghcr.io/acme/api", digest),
+			expectIssue: false,
+		},
+		{
+			name:        "latest tag",
+			service:     NewWebService("api", RuntimeImage).WithDockerImage("ghcr.io/acme/api:latest"),
+			expectIssue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(tt.service)
+			issue := findIssue(ValidateBlueprintDetailed(bp), CodeMutableImageTag)
+
+			if !tt.expectIssue {
+				if issue != nil {
+					t.Errorf("expected no mutable-image-tag issue, got %q", issue.Message)
+				}
+				return
+			}
+			if issue == nil {
+				t.Fatal("expected a mutable-image-tag issue")
+			}
+			if issue.Severity != SeverityWarning {
+				t.Errorf("expected a warning, got %s", issue.Severity)
+			}
+		})
+	}
+}
+
+func TestValidateDomainsOnlyOnWebServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  Service
+		expected []string
+	}{
+		{
+			name:     "worker with domains",
+			service:  Service{Name: "worker", Type: ServiceTypeWorker, Runtime: runtimePtr(RuntimeNode), Domains: []string{"worker.example.com"}},
+			expected: []string{"worker service worker must not set domains"},
+		},
+		{
+			name:     "web service with domains",
+			service:  *NewWebService("api", RuntimeNode).WithStartCommand("npm start").WithDomains("api.example.com").ToService(),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := &Blueprint{Services: []Service{tt.service}}
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeMisplacedDomains {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateNameCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		opts     ValidationOptions
+		expected []string
+	}{
+		{
+			name: "case-only service collision",
+			bp: NewBlueprint().WithServices(
+				NewBackgroundWorker("API", RuntimeNode),
+				NewBackgroundWorker("api", RuntimeNode),
+			),
+			expected: []string{"service name collision (case-insensitive): API vs api"},
+		},
+		{
+			name: "case-only group collision",
+			bp: NewBlueprint().WithEnvVarGroups(
+				NewEnvVarGroup("Shared").WithEnv("A", "1"),
+				NewEnvVarGroup("shared").WithEnv("B", "2"),
+			),
+			expected: []string{"environment group name collision (case-insensitive): Shared vs shared"},
+		},
+		{
+			name: "distinct names",
+			bp: NewBlueprint().WithServices(
+				NewBackgroundWorker("api", RuntimeNode),
+				NewBackgroundWorker("api-worker", RuntimeNode),
+			),
+			expected: nil,
+		},
+		{
+			name: "check turned off",
+			bp: NewBlueprint().WithServices(
+				NewBackgroundWorker("API", RuntimeNode),
+				NewBackgroundWorker("api", RuntimeNode),
+			),
+			opts:     ValidationOptions{CaseSensitiveNames: true},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintWithOptions(tt.bp, tt.opts) {
+				if issue.Code == CodeNameCaseCollision {
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateHighAvailabilityPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     Plan
+		expected []string
+	}{
+		{
+			name:     "basic plan",
+			plan:     PlanBasic256MB,
+			expected: []string{"database main-db has high availability but plan basic-256mb does not support it"},
+		},
+		{
+			name:     "pro plan",
+			plan:     PlanPro8GB,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithDatabases(NewDatabase("main-db").WithPlan(tt.plan).WithHighAvailability())
+
+			var messages []string
+			for _, issue := range bp.ValidatePlanFeatures() {
+				if issue.Code == CodeHANotSupported {
+					if issue.Severity != SeverityWarning {
+						t.Errorf("expected a warning, got %s", issue.Severity)
+					}
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateEnvVarValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "multiline value",
+			value:    "line1\nline2",
+			expected: []string{"service api env var TLS_CERT contains newlines or control characters; consider base64-encoding it or using a secret"},
+		},
+		{
+			name:     "single-line value",
+			value:    "plain",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := NewBlueprint().WithServices(NewWebService("api", RuntimeNode).WithEnv("TLS_CERT", tt.value))
+
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(bp) {
+				if issue.Code == CodeEnvValueControlChars {
+					if issue.Severity != SeverityWarning {
+						t.Errorf("expected a warning, got %s", issue.Severity)
+					}
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultDomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		bp       *Blueprint
+		expected []string
+	}{
+		{
+			name: "default domain claimed by another service",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode),
+				NewWebService("web", RuntimeNode).WithDomains("api.onrender.com"),
+			),
+			expected: []string{"service api default domain api.onrender.com conflicts with explicit domain on web"},
+		},
+		{
+			name: "clean set",
+			bp: NewBlueprint().WithServices(
+				NewWebService("api", RuntimeNode).WithDomains("api.example.com"),
+				NewWebService("web", RuntimeNode).WithDomains("www.example.com"),
+			),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range ValidateBlueprintDetailed(tt.bp) {
+				if issue.Code == CodeDefaultDomainConflict {
+					if issue.Severity != SeverityWarning {
+						t.Errorf("expected a warning, got %s", issue.Severity)
+					}
+					messages = append(messages, issue.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(messages, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateBlueprintStrict(t *testing.T) {
+	bp := NewBlueprint().
+		WithServices(NewBackgroundWorker("api", RuntimeNode).
+			WithDatabaseURL("main-db").
+			WithEnvVars(EnvFromGroup("shared"))).
+		WithEnvVarGroups(NewEnvVarGroup("shared").WithEnv("LOG_LEVEL", "info"))
+
+	// External references are allowed by default
+	if errors := ValidateBlueprint(bp); len(errors) != 0 {
+		t.Errorf("expected no errors without strict references, got %v", errors)
+	}
+
+	expected := []string{"service api references unknown database main-db"}
+	if errors := ValidateBlueprintStrict(bp); !reflect.DeepEqual(errors, expected) {
+		t.Errorf("expected %v, got %v", expected, errors)
+	}
+
+	bp.WithDatabases(NewDatabase("main-db"))
+	if errors := ValidateBlueprintStrict(bp); len(errors) != 0 {
+		t.Errorf("expected resolved references to pass, got %v", errors)
+	}
+}
+
+// Helper functions for validation tests
+
+func decodeIssues(t *testing.T, data []byte) []ValidationIssue {
+	t.Helper()
+	var issues []ValidationIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if issues == nil {
+		t.Fatalf("expected a JSON array, got %s", data)
+	}
+	return issues
+}
+
+func findIssue(issues []ValidationIssue, code string) *ValidationIssue {
+	for i := range issues {
+		if issues[i].Code == code {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+func findIssuesBySeverity(issues []ValidationIssue, severity Severity) []ValidationIssue {
+	var matched []ValidationIssue
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			matched = append(matched, issue)
+		}
+	}
+	return matched
+}